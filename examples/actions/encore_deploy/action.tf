@@ -0,0 +1,7 @@
+action "encore_deploy" "staging" {
+  config {
+    env     = "staging"
+    branch  = "main"
+    timeout = "45m"
+  }
+}
@@ -0,0 +1,20 @@
+# Restart the service whenever its IAM policy changes,
+# so that it picks up the new permissions.
+resource "aws_iam_role_policy" "api" {
+  role   = "my-app-production-api"
+  policy = data.aws_iam_policy_document.api.json
+
+  lifecycle {
+    action_trigger {
+      events  = [after_update]
+      actions = [action.encore_restart_service.api]
+    }
+  }
+}
+
+action "encore_restart_service" "api" {
+  config {
+    env     = "production"
+    service = "api"
+  }
+}
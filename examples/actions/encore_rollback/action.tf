@@ -0,0 +1,5 @@
+action "encore_rollback" "production" {
+  config {
+    env = "production"
+  }
+}
@@ -0,0 +1,16 @@
+# Write a snapshot of the staging environment, e.g. with
+# `terraform apply -invoke=action.encore_snapshot.staging`,
+# and commit it for use in an offline pipeline.
+action "encore_snapshot" "staging" {
+  config {
+    env  = "staging"
+    path = "${path.module}/snapshots/staging.json"
+  }
+}
+
+# In the offline pipeline, read data sources from the snapshots.
+provider "encore" {
+  alias         = "offline"
+  snapshot_file = "${path.module}/snapshots"
+  env           = "staging"
+}
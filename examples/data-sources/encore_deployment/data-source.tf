@@ -0,0 +1,9 @@
+data "encore_deployment" "production" {
+  env = "production"
+}
+
+# Deploy the commit currently running in production to staging.
+resource "encore_deployment" "staging" {
+  env    = "staging"
+  commit = data.encore_deployment.production.commit
+}
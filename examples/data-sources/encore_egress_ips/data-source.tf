@@ -0,0 +1,13 @@
+data "encore_egress_ips" "production" {
+  env = "production"
+}
+
+# Allow the production environment to reach a partner database.
+resource "aws_security_group_rule" "partner_db" {
+  type              = "ingress"
+  from_port         = 5432
+  to_port           = 5432
+  protocol          = "tcp"
+  cidr_blocks       = [for ip in data.encore_egress_ips.production.ips : "${ip}/32"]
+  security_group_id = var.partner_db_security_group_id
+}
@@ -3,13 +3,38 @@ data "encore_gateway" "gateway" {
   env  = "my-env"
 }
 
+# Point a custom domain at the load balancer of the gateway on AWS.
+resource "aws_route53_record" "api" {
+  zone_id = var.zone_id
+  name    = "api.example.com"
+  type    = "A"
+
+  alias {
+    name                   = data.encore_gateway.gateway.aws_alb.dns_name
+    zone_id                = data.encore_gateway.gateway.aws_alb.zone_id
+    evaluate_target_health = true
+  }
+}
+
+# Protect the load balancer of the gateway on AWS with a WAF web ACL.
+resource "aws_wafv2_web_acl_association" "api" {
+  resource_arn = data.encore_gateway.gateway.aws_alb.arn
+  web_acl_arn  = var.web_acl_arn
+}
+
+output "base_url" {
+  value = data.encore_gateway.gateway.base_url
+}
 
 output "aws_fargate" {
   value = {
     "load_balancer" : data.encore_gateway.gateway.aws_alb.arn,
+    "certificate" : data.encore_gateway.gateway.aws_alb.certificate_arn,
     "listener" : data.encore_gateway.gateway.aws_alb.listeners.0.arn,
     "listener_port" : data.encore_gateway.gateway.aws_alb.listeners.0.port,
     "listener_protocol" : data.encore_gateway.gateway.aws_alb.listeners.0.protocol,
+    "target_group" : data.encore_gateway.gateway.aws_alb.target_groups.0.arn,
+    "access_log_bucket" : data.encore_gateway.gateway.aws_alb.access_log_bucket,
     "taskdef_arn" : data.encore_gateway.gateway.aws_fargate_task_definition.arn,
     "service" : data.encore_gateway.gateway.aws_fargate_task_definition.service.arn,
     "cluster" : data.encore_gateway.gateway.aws_fargate_task_definition.service.cluster.arn,
@@ -24,15 +49,27 @@ output "aws_fargate" {
 output "gcp_cloudrun" {
   value = {
     "id" : data.encore_gateway.gateway.gcp_cloud_run.id,
+    "url" : data.encore_gateway.gateway.gcp_cloud_run.url,
     "service_account" : data.encore_gateway.gateway.gcp_cloud_run.service_account.id,
     "serverless_vpc_connector" : data.encore_gateway.gateway.gcp_cloud_run.serverless_vpc_connector.id,
     "network" : data.encore_gateway.gateway.gcp_cloud_run.serverless_vpc_connector.network.id
   }
 }
 
+output "gcp_load_balancer" {
+  value = {
+    "ip_address" : data.encore_gateway.gateway.gcp_load_balancer.ip_address.address,
+    "backend_service" : data.encore_gateway.gateway.gcp_load_balancer.backend_service.id,
+    "url_map" : data.encore_gateway.gateway.gcp_load_balancer.url_map.id,
+    "forwarding_rule" : data.encore_gateway.gateway.gcp_load_balancer.forwarding_rules.0.id,
+    "serverless_neg" : data.encore_gateway.gateway.gcp_load_balancer.serverless_neg.id
+  }
+}
+
 output "k8s_deployment" {
   value = {
     "ingress" : data.encore_gateway.gateway.k8s_ingress.name,
+    "ingress_ip" : data.encore_gateway.gateway.k8s_ingress.static_ip,
     "deployment" : data.encore_gateway.gateway.k8s_deployment.name,
     "namespace" : data.encore_gateway.gateway.k8s_deployment.namespace.name,
     "service_account" : data.encore_gateway.gateway.k8s_deployment.service_account.name,
@@ -7,6 +7,9 @@ output "aws_rds" {
   value = {
     "db_name" : data.encore_sql_database.database.database_name,
     "rds_arn" : data.encore_sql_database.database.aws_rds.arn,
+    "endpoint" : "${data.encore_sql_database.database.aws_rds.endpoint}:${data.encore_sql_database.database.aws_rds.port}",
+    "engine_version" : data.encore_sql_database.database.aws_rds.engine_version,
+    "instance_class" : data.encore_sql_database.database.aws_rds.instance_class,
     "vpc" : data.encore_sql_database.database.aws_rds.vpc.id,
     "subnet_group" : data.encore_sql_database.database.aws_rds.subnet_group.arn,
     "security_group" : data.encore_sql_database.database.aws_rds.security_group.id,
@@ -21,7 +24,27 @@ output "gcp_cloud_sql" {
   value = {
     "database_name" : data.encore_sql_database.database.database_name,
     "cloud_sql_id" : data.encore_sql_database.database.gcp_cloud_sql.id,
+    "endpoint" : "${data.encore_sql_database.database.gcp_cloud_sql.endpoint}:${data.encore_sql_database.database.gcp_cloud_sql.port}",
+    "engine_version" : data.encore_sql_database.database.gcp_cloud_sql.engine_version,
     "network" : data.encore_sql_database.database.gcp_cloud_sql.network.id,
     "ssl_cert_fingerprint" : data.encore_sql_database.database.gcp_cloud_sql.ssl_cert.fingerprint
   }
 }
+
+output "aws_aurora" {
+  value = {
+    "cluster_arn" : data.encore_sql_database.database.aws_aurora.arn,
+    "endpoint" : data.encore_sql_database.database.aws_aurora.endpoint,
+    "reader_endpoint" : data.encore_sql_database.database.aws_aurora.reader_endpoint,
+    "writer" : data.encore_sql_database.database.aws_aurora.writer.arn,
+    "readers" : [for r in data.encore_sql_database.database.aws_aurora.readers : r.arn]
+  }
+}
+
+# Give read-only analytics access to the replicas of the database.
+output "read_replicas" {
+  value = concat(
+    [for r in try(data.encore_sql_database.database.aws_rds_replica_set.replicas, []) : "${r.endpoint}:${r.port}"],
+    [for r in try(data.encore_sql_database.database.gcp_cloud_sql_replica_set.replicas, []) : "${r.endpoint}:${r.port}"],
+  )
+}
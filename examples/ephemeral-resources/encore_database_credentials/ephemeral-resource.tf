@@ -0,0 +1,13 @@
+ephemeral "encore_database_credentials" "analytics" {
+  env       = "my-env"
+  name      = "analytics"
+  read_only = true
+}
+
+provider "postgresql" {
+  host     = ephemeral.encore_database_credentials.analytics.host
+  port     = ephemeral.encore_database_credentials.analytics.port
+  database = ephemeral.encore_database_credentials.analytics.database
+  username = ephemeral.encore_database_credentials.analytics.user
+  password = ephemeral.encore_database_credentials.analytics.password
+}
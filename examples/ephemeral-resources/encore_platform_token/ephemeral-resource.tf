@@ -0,0 +1,8 @@
+ephemeral "encore_platform_token" "api" {}
+
+provider "restapi" {
+  uri = "https://api.encore.dev"
+  headers = {
+    Authorization = "${ephemeral.encore_platform_token.api.token_type} ${ephemeral.encore_platform_token.api.access_token}"
+  }
+}
@@ -0,0 +1,7 @@
+list "encore_custom_domain" "all" {
+  provider = encore
+
+  config {
+    env = "my-env"
+  }
+}
@@ -0,0 +1,9 @@
+list "encore_deployment" "successful" {
+  provider = encore
+  limit    = 10
+
+  config {
+    env    = "my-env"
+    status = "success"
+  }
+}
@@ -0,0 +1,7 @@
+import {
+  to = encore_custom_domain.api
+  identity = {
+    env      = "my-env"
+    hostname = "api.example.com"
+  }
+}
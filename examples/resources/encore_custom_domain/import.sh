@@ -0,0 +1,2 @@
+# Custom domains can be imported using the environment name and hostname, e.g.
+terraform import encore_custom_domain.api my-env/api.example.com
@@ -0,0 +1,21 @@
+resource "encore_custom_domain" "api" {
+  env         = "my-env"
+  hostname    = "api.example.com"
+  path_prefix = "/v1"
+}
+
+resource "aws_route53_record" "api_validation" {
+  zone_id = aws_route53_zone.example.zone_id
+  name    = encore_custom_domain.api.validation_records.0.name
+  type    = encore_custom_domain.api.validation_records.0.type
+  records = [encore_custom_domain.api.validation_records.0.value]
+  ttl     = 300
+}
+
+resource "aws_route53_record" "api" {
+  zone_id = aws_route53_zone.example.zone_id
+  name    = encore_custom_domain.api.target_records.0.name
+  type    = encore_custom_domain.api.target_records.0.type
+  records = [encore_custom_domain.api.target_records.0.value]
+  ttl     = 300
+}
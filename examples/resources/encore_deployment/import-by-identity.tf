@@ -0,0 +1,7 @@
+import {
+  to = encore_deployment.staging
+  identity = {
+    env = "staging"
+    id  = "deploy_123"
+  }
+}
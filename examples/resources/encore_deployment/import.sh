@@ -0,0 +1,2 @@
+# Deployments can be imported using the environment name and deployment id, e.g.
+terraform import encore_deployment.staging staging/deploy_123
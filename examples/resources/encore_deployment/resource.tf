@@ -0,0 +1,8 @@
+resource "encore_deployment" "staging" {
+  env    = "staging"
+  commit = "9fceb02d0ae598e95dc970b74767f19372d61af8"
+
+  timeouts = {
+    create = "45m"
+  }
+}
@@ -0,0 +1,97 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/action"
+	"github.com/hashicorp/terraform-plugin-framework/action/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// platformAction holds the state shared by all actions operating on the Encore Platform.
+type platformAction struct {
+	client     PlatformClient
+	defaultEnv string
+}
+
+func (a *platformAction) Configure(ctx context.Context, req action.ConfigureRequest, resp *action.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	needs, ok := req.ProviderData.(*NeedsData)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Action Configure Type",
+			fmt.Sprintf("Expected *NeedsData, received %T", req.ProviderData),
+		)
+		return
+	}
+
+	a.client = needs.client
+	a.defaultEnv = needs.defaultEnv
+}
+
+// env returns the configured environment, or the provider environment if unset.
+func (a *platformAction) env(env types.String) string {
+	if env.ValueString() == "" {
+		return a.defaultEnv
+	}
+	return env.ValueString()
+}
+
+// timeoutAttribute returns the schema of the timeout of an action waiting
+// for the operation described by what to complete.
+func timeoutAttribute(what string) schema.StringAttribute {
+	return schema.StringAttribute{
+		MarkdownDescription: fmt.Sprintf("How long to wait for the %s to complete, as a duration such as `45m` or `1h30m`. Defaults to `30m`", what),
+		Optional:            true,
+	}
+}
+
+// actionTimeout returns the configured timeout, or the default deployment
+// timeout if unset.
+func actionTimeout(timeout types.String) (time.Duration, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	if timeout.IsNull() || timeout.IsUnknown() {
+		return defaultDeploymentCreateTimeout, nil
+	}
+	d, err := time.ParseDuration(timeout.ValueString())
+	if err != nil || d <= 0 {
+		diags.AddAttributeError(path.Root("timeout"), "Invalid Timeout",
+			fmt.Sprintf("Expected a positive duration such as \"45m\", got %q", timeout.ValueString()))
+	}
+	return d, diags
+}
+
+// progress logs msg and reports it to Terraform.
+func (a *platformAction) progress(ctx context.Context, resp *action.InvokeResponse, msg string) {
+	tflog.Info(ctx, msg)
+	if resp.SendProgress != nil {
+		resp.SendProgress(action.InvokeProgressEvent{Message: msg})
+	}
+}
+
+// waitForDeployment reports the progress of deploy until it is done,
+// or timeout has passed.
+func (a *platformAction) waitForDeployment(ctx context.Context, resp *action.InvokeResponse, envName string, deploy *Deployment, timeout time.Duration) (diags diag.Diagnostics) {
+	a.progress(ctx, resp, fmt.Sprintf("Deployment %s of commit %s to %s is %s", deploy.ID, deploy.Commit, envName, deploy.Status))
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	err := waitForDeployment(ctx, a.client, envName, deploy, func(deploy *Deployment) {
+		a.progress(ctx, resp, fmt.Sprintf("Deployment %s is %s", deploy.ID, deploy.Status))
+	})
+	if err != nil {
+		diags.AddError("Deployment Error", fmt.Sprintf("Unable to wait for deployment %s to complete, got error: %s", deploy.ID, err))
+	} else if deploy.Status != DeploymentSuccess {
+		diags.AddError("Deployment Error", deploymentFailure(deploy))
+	}
+	return diags
+}
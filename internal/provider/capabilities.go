@@ -0,0 +1,306 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hasura/go-graphql-client"
+	"github.com/hasura/go-graphql-client/ident"
+)
+
+// platformSchema describes the GraphQL schema served by the platform, keyed by type name.
+type platformSchema map[string]*schemaType
+
+type schemaType struct {
+	// Fields maps the name of each field to the name of its (unwrapped) type.
+	Fields map[string]string
+	// PossibleTypes are the members of a union or the implementations of an interface.
+	PossibleTypes []string
+}
+
+// introspectionTypeRef is a reference to a possibly wrapped type, such
+// as a non-null list of non-null objects.
+type introspectionTypeRef struct {
+	Name   string
+	OfType struct {
+		Name   string
+		OfType struct {
+			Name   string
+			OfType struct {
+				Name string
+			}
+		}
+	}
+}
+
+func (r introspectionTypeRef) named() string {
+	for _, name := range []string{r.Name, r.OfType.Name, r.OfType.OfType.Name, r.OfType.OfType.OfType.Name} {
+		if name != "" {
+			return name
+		}
+	}
+	return ""
+}
+
+type introspectionQuery struct {
+	Schema struct {
+		QueryType struct {
+			Name string
+		}
+		Types []struct {
+			Name   string
+			Fields []struct {
+				Name string
+				Type introspectionTypeRef
+			}
+			PossibleTypes []struct {
+				Name string
+			}
+		}
+	} `graphql:"__schema"`
+}
+
+// introspect fetches the GraphQL schema of the platform, returning it
+// along with the name of its query type.
+func introspect(ctx context.Context, client *graphql.Client) (platformSchema, string, error) {
+	var q introspectionQuery
+	if err := client.Query(ctx, &q, nil, graphql.OperationName("Capabilities")); err != nil {
+		return nil, "", err
+	} else if q.Schema.QueryType.Name == "" {
+		return nil, "", errors.New("no schema returned")
+	}
+	schema := platformSchema{}
+	for _, t := range q.Schema.Types {
+		st := &schemaType{Fields: map[string]string{}}
+		for _, f := range t.Fields {
+			st.Fields[f.Name] = f.Type.named()
+		}
+		for _, pt := range t.PossibleTypes {
+			st.PossibleTypes = append(st.PossibleTypes, pt.Name)
+		}
+		schema[t.Name] = st
+	}
+	return schema, q.Schema.QueryType.Name, nil
+}
+
+// droppedField is a field of the needs query that the platform does not support.
+type droppedField struct {
+	// Satisfier is the satisfier type the field belongs to, if any.
+	Satisfier string
+	// GraphQL is the path of the field in the GraphQL query.
+	GraphQL string
+	// Attribute is the path of the corresponding Terraform attribute,
+	// relative to the data source, or empty for a whole satisfier type.
+	Attribute string
+}
+
+// queryBuilder writes the selection set of a query the way the GraphQL
+// client does, leaving out the fields and fragments that are not in schema.
+type queryBuilder struct {
+	schema  platformSchema
+	dropped []droppedField
+}
+
+var (
+	jsonUnmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+	satisfierQueryType  = reflect.TypeOf(SatisfierQuery{})
+)
+
+// write writes the selection set of t, of the GraphQL type gqlType, and
+// returns the number of fields written other than __typename, which alone
+// would leave an object without attributes. The satisfier and attribute
+// paths of the selection are tracked for reporting dropped fields.
+func (b *queryBuilder) write(w *strings.Builder, t reflect.Type, gqlType string, inline bool, satisfier string, gqlPath, attrPath []string) int {
+	for t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct || reflect.PointerTo(t).Implements(jsonUnmarshalerType) {
+		return 0
+	}
+	if t == satisfierQueryType {
+		// Attributes are named relative to the satisfier.
+		attrPath = nil
+	}
+	if !inline {
+		w.WriteString("{")
+	}
+	st := b.schema[gqlType]
+	n, typenames := 0, 0
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag, hasTag := f.Tag.Lookup("graphql")
+		if tag == "-" {
+			continue
+		}
+
+		fieldSatisfier, fieldType, fieldAttrPath := satisfier, gqlType, attrPath
+		if tfName := getTFName(f); tfName != "" {
+			fieldAttrPath = append(slices.Clip(attrPath), tfName)
+		}
+		inlineField := f.Anonymous && !hasTag
+		var name, fieldName string
+		switch {
+		case inlineField:
+		case strings.HasPrefix(tag, "... on "):
+			fieldType = strings.TrimPrefix(tag, "... on ")
+			name, fieldName = tag, tag
+			if t == satisfierQueryType {
+				fieldSatisfier = fieldType
+			}
+			if st != nil && fieldType != gqlType && !slices.Contains(st.PossibleTypes, fieldType) {
+				b.drop(fieldSatisfier, append(slices.Clip(gqlPath), fieldName), fieldAttrPath)
+				continue
+			}
+		default:
+			name = tag
+			if !hasTag {
+				name = ident.ParseMixedCaps(f.Name).ToLowerCamelCase()
+			}
+			fieldName, _, _ = strings.Cut(name, "(")
+			if fieldName != "__typename" && st != nil {
+				typ, ok := st.Fields[fieldName]
+				if !ok {
+					b.drop(fieldSatisfier, append(slices.Clip(gqlPath), fieldName), fieldAttrPath)
+					continue
+				}
+				fieldType = typ
+			}
+		}
+
+		fieldGQLPath := gqlPath
+		if fieldName != "" {
+			fieldGQLPath = append(slices.Clip(gqlPath), fieldName)
+		}
+		var fw strings.Builder
+		fw.WriteString(name)
+		if f.Tag.Get("scalar") == "true" {
+			w.WriteString(sep(&n))
+			w.WriteString(fw.String())
+			continue
+		}
+		dropped := len(b.dropped)
+		written := b.write(&fw, f.Type, fieldType, inlineField, fieldSatisfier, fieldGQLPath, fieldAttrPath)
+		if b.schema != nil && written == 0 && isSelection(f.Type) {
+			// An empty selection set is not valid GraphQL, so leave out
+			// the whole field. It is reported as a whole unless its
+			// attributes are flattened into those of its parent.
+			if !inlineField && getTFName(f) != "" {
+				b.dropped = b.dropped[:dropped]
+				b.drop(fieldSatisfier, fieldGQLPath, fieldAttrPath)
+			}
+			continue
+		}
+
+		w.WriteString(sep(&n))
+		w.WriteString(fw.String())
+		if fieldName == "__typename" {
+			typenames++
+		}
+	}
+	if !inline {
+		w.WriteString("}")
+	}
+	return n - typenames
+}
+
+// sep returns the separator to write before the field following the n
+// fields written so far, and counts the field.
+func sep(n *int) string {
+	*n++
+	if *n == 1 {
+		return ""
+	}
+	return ","
+}
+
+// isSelection reports whether the GraphQL client queries the fields of t,
+// rather than treating it as a scalar.
+func isSelection(t reflect.Type) bool {
+	for t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice {
+		t = t.Elem()
+	}
+	return t.Kind() == reflect.Struct && !reflect.PointerTo(t).Implements(jsonUnmarshalerType)
+}
+
+func (b *queryBuilder) drop(satisfier string, gqlPath, attrPath []string) {
+	d := droppedField{Satisfier: satisfier, GraphQL: strings.Join(gqlPath, "."), Attribute: strings.Join(attrPath, ".")}
+	b.dropped = append(b.dropped, d)
+}
+
+// buildQuery returns the GraphQL query for v with the given variables and
+// options, leaving out the fields that are not in schema. All fields are
+// kept if schema is nil.
+func buildQuery(v interface{}, schema platformSchema, rootType string, variables map[string]interface{}, options ...graphql.Option) (string, []droppedField, error) {
+	// The header of the query is that of an empty selection set.
+	header, err := graphql.ConstructQuery(&struct{}{}, variables, options...)
+	if err != nil {
+		return "", nil, err
+	}
+	b := &queryBuilder{schema: schema}
+	var w strings.Builder
+	w.WriteString(strings.TrimSuffix(header, "{}"))
+	b.write(&w, reflect.TypeOf(v), rootType, false, "", nil, nil)
+	return w.String(), b.dropped, nil
+}
+
+// introspectTimeout bounds the introspection of the platform schema.
+const introspectTimeout = 30 * time.Second
+
+// capabilities returns the platform GraphQL schema, introspecting it on
+// first use. It returns nil if the schema is not available, in which case
+// queries are sent in full.
+func (n *NeedsData) capabilities(ctx context.Context) (platformSchema, string) {
+	n.schemaOnce.Do(func() {
+		// The schema is used for the rest of the run, so it must not be
+		// lost if the read that happens to introspect it is canceled.
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), introspectTimeout)
+		defer cancel()
+		schema, rootType, err := introspect(ctx, n.client.GQL())
+		if err != nil {
+			tflog.SubsystemDebug(platformLogContext(ctx), logSubsystem, "unable to introspect platform schema, assuming all fields are supported", map[string]interface{}{"error": err.Error()})
+			return
+		}
+		// The fields left out of the query depend on the schema alone.
+		_, dropped, err := buildQuery(&needsQuery{}, schema, rootType, nil)
+		if err != nil {
+			tflog.SubsystemDebug(platformLogContext(ctx), logSubsystem, "unable to build needs query, assuming all fields are supported", map[string]interface{}{"error": err.Error()})
+			return
+		}
+		n.schema, n.rootType, n.dropped = schema, rootType, dropped
+	})
+	return n.schema, n.rootType
+}
+
+// needsQueryString returns the needs query with the given variables,
+// limited to the fields the platform supports, along with the fields
+// that are left out.
+func (n *NeedsData) needsQueryString(ctx context.Context, variables map[string]interface{}) (string, []droppedField, error) {
+	schema, rootType := n.capabilities(ctx)
+	query, _, err := buildQuery(&needsQuery{}, schema, rootType, variables, graphql.OperationName(needsOperation))
+	return query, n.dropped, err
+}
+
+// droppedWarning describes the attributes that are null because the
+// platform does not support them.
+func droppedWarning(dropped []droppedField) string {
+	var lines []string
+	for _, d := range dropped {
+		switch {
+		case d.Attribute == "":
+			lines = append(lines, fmt.Sprintf("- resources provisioned as %s (%s)", d.Satisfier, d.GraphQL))
+		case d.Satisfier == "":
+			lines = append(lines, fmt.Sprintf("- %s (%s)", d.Attribute, d.GraphQL))
+		default:
+			lines = append(lines, fmt.Sprintf("- %s of resources provisioned as %s (%s)", d.Attribute, d.Satisfier, d.GraphQL))
+		}
+	}
+	return "The Encore Platform does not support all fields used by this provider version yet. " +
+		"The following attributes will be null:\n\n" + strings.Join(lines, "\n")
+}
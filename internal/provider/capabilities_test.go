@@ -0,0 +1,284 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hasura/go-graphql-client"
+	"github.com/hasura/go-graphql-client/ident"
+)
+
+// testSchema returns the schema of a platform that supports every field
+// of the query v, naming types after their Go types.
+func testSchema(v interface{}) (platformSchema, string) {
+	schema := platformSchema{}
+	var walk func(t reflect.Type, name string)
+	walk = func(t reflect.Type, name string) {
+		for t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice {
+			t = t.Elem()
+		}
+		if !isSelection(t) || schema[name] != nil {
+			return
+		}
+		st := &schemaType{Fields: map[string]string{}}
+		schema[name] = st
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			tag, hasTag := f.Tag.Lookup("graphql")
+			fieldType := f.Type
+			for fieldType.Kind() == reflect.Ptr || fieldType.Kind() == reflect.Slice {
+				fieldType = fieldType.Elem()
+			}
+			typeName := fieldType.Name()
+			if typeName == "" {
+				typeName = f.Name
+			}
+			switch {
+			case f.Anonymous && !hasTag:
+				walk(f.Type, name)
+				schema[name] = st
+			case strings.HasPrefix(tag, "... on "):
+				member := strings.TrimPrefix(tag, "... on ")
+				st.PossibleTypes = append(st.PossibleTypes, member)
+				walk(f.Type, member)
+			default:
+				fieldName := tag
+				if !hasTag {
+					fieldName = ident.ParseMixedCaps(f.Name).ToLowerCamelCase()
+				}
+				fieldName, _, _ = strings.Cut(fieldName, "(")
+				st.Fields[fieldName] = typeName
+				walk(f.Type, typeName)
+			}
+		}
+	}
+	walk(reflect.TypeOf(v), "Query")
+	return schema, "Query"
+}
+
+func TestBuildQuery(t *testing.T) {
+	vars := map[string]interface{}{
+		"appSlug": "app",
+		"envName": "env",
+		"types":   []TypeRef{"PubSubSubscription"},
+	}
+	want, err := graphql.ConstructQuery(&needsQuery{}, vars, graphql.OperationName(needsOperation))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("no schema", func(t *testing.T) {
+		got, dropped, err := buildQuery(&needsQuery{}, nil, "", vars, graphql.OperationName(needsOperation))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != want || len(dropped) > 0 {
+			t.Errorf("got query %s dropping %v, want %s", got, dropped, want)
+		}
+	})
+
+	t.Run("full schema", func(t *testing.T) {
+		schema, root := testSchema(&needsQuery{})
+		got, dropped, err := buildQuery(&needsQuery{}, schema, root, vars, graphql.OperationName(needsOperation))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != want || len(dropped) > 0 {
+			t.Errorf("got query %s dropping %v, want %s", got, dropped, want)
+		}
+	})
+
+	t.Run("older schema", func(t *testing.T) {
+		schema, root := testSchema(&needsQuery{})
+		delete(schema["AWSSQSQueue"].Fields, "dlq")
+		delete(schema["AWSSNSTopic"].Fields, "arn")
+		satisfier := schema["SatisfierQuery"]
+		satisfier.PossibleTypes = slices.DeleteFunc(satisfier.PossibleTypes, func(s string) bool { return s == "GCPPubSubTopic" })
+
+		got, dropped, err := buildQuery(&needsQuery{}, schema, root, vars, graphql.OperationName(needsOperation))
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, unwanted := range []string{"{__typename,... on AWSSNSSubscription{arn,topic", "queue{arn,dlq", "},... on GCPPubSubTopic", "},... on AWSSNSTopic", "{}"} {
+			if strings.Contains(got, unwanted) {
+				t.Errorf("got query containing %q: %s", unwanted, got)
+			}
+		}
+		for _, wanted := range []string{"... on AWSSNSSubscription{arn,queue{arn}}", "... on GCPPubSubSubscription{selfLink,topic{... on GCPPubSubTopic{selfLink}}"} {
+			if !strings.Contains(got, wanted) {
+				t.Errorf("got query without %q: %s", wanted, got)
+			}
+		}
+
+		wantDropped := []droppedField{
+			{Satisfier: "AWSSNSSubscription", GraphQL: "app.env.needs.satisfier.... on AWSSNSSubscription.topic.... on AWSSNSTopic", Attribute: "aws_sns.topic"},
+			{Satisfier: "AWSSNSSubscription", GraphQL: "app.env.needs.satisfier.... on AWSSNSSubscription.queue.dlq", Attribute: "aws_sns.queue.dead_letter"},
+			{Satisfier: "AWSSNSTopic", GraphQL: "app.env.needs.satisfier.... on AWSSNSTopic", Attribute: "aws_sns"},
+			{Satisfier: "GCPPubSubTopic", GraphQL: "app.env.needs.satisfier.... on GCPPubSubTopic", Attribute: "gcp_pubsub"},
+		}
+		if !reflect.DeepEqual(dropped, wantDropped) {
+			t.Errorf("got dropped fields %+v, want %+v", dropped, wantDropped)
+		}
+	})
+}
+
+// olderTestSchema returns the schema of a platform that does not support
+// the dead letter queue of AWS SQS queues.
+func olderTestSchema() (platformSchema, string) {
+	schema, root := testSchema(&needsQuery{})
+	delete(schema["AWSSQSQueue"].Fields, "dlq")
+	return schema, root
+}
+
+func TestNeedsDataCapabilities(t *testing.T) {
+	platform := newFakePlatform(newTestPlatformState())
+	defer platform.Close()
+	platform.setSchema(olderTestSchema())
+
+	ctx := context.Background()
+	client := platform.newClient("test", ClientOptions{})
+	if err := client.Auth(ctx, "test"); err != nil {
+		t.Fatal(err)
+	}
+	n := NewNeedsData(client, "eks", []func() datasource.DataSource{NewPubSubSubscription})
+	_, diags := n.envNeeds(ctx, needsKey{app: "test", env: "eks"})
+	if diags.HasError() || len(diags.Warnings()) != 1 {
+		t.Fatalf("got diagnostics %v, want a warning", diags)
+	}
+	if detail := diags.Warnings()[0].Detail(); !strings.Contains(detail, "aws_sns.queue.dead_letter of resources provisioned as AWSSNSSubscription") {
+		t.Errorf("got warning %q", detail)
+	}
+
+	// The warning is only emitted once per run.
+	_, diags = n.envNeeds(ctx, needsKey{app: "test", env: "fargate"})
+	if len(diags) > 0 {
+		t.Errorf("got diagnostics %v, want none", diags)
+	}
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: platform.factories(),
+		Steps: []resource.TestStep{{
+			Config: fmt.Sprintf(testPubsubSubDataSourceConfig, "eks"),
+			Check: resource.ComposeAggregateTestCheckFunc(
+				resource.TestCheckResourceAttr("data.encore_pubsub_subscription.subscription", "aws_sns.queue.arn", "arn:aws:sqs:region:account:app-env-events-log-event"),
+				resource.TestCheckNoResourceAttr("data.encore_pubsub_subscription.subscription", "aws_sns.queue.dead_letter.arn"),
+			),
+		}},
+	})
+}
+
+func TestNeedsDataConcurrent(t *testing.T) {
+	platform := newFakePlatform(newTestPlatformState())
+	defer platform.Close()
+	platform.setSchema(olderTestSchema())
+
+	ctx := context.Background()
+	client := platform.newClient("test", ClientOptions{})
+	if err := client.Auth(ctx, "test"); err != nil {
+		t.Fatal(err)
+	}
+	n := NewNeedsData(client, "eks", []func() datasource.DataSource{NewPubSubSubscription})
+
+	// Terraform reads data sources of several environments concurrently.
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		warnings int
+	)
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			env := []string{"eks", "fargate"}[i%2]
+			_, diags := n.envNeeds(ctx, needsKey{app: "test", env: env})
+			if diags.HasError() {
+				t.Errorf("got diagnostics %v for %s", diags, env)
+			}
+			mu.Lock()
+			warnings += len(diags.Warnings())
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+	if warnings != 1 {
+		t.Errorf("got %d warnings, want 1", warnings)
+	}
+	if len(n.dropped) != 1 || n.dropped[0].Attribute != "aws_sns.queue.dead_letter" {
+		t.Errorf("got dropped fields %+v, want the dead letter queue", n.dropped)
+	}
+}
+
+func TestNeedsDataCapabilitiesCanceled(t *testing.T) {
+	platform := newFakePlatform(newTestPlatformState())
+	defer platform.Close()
+	platform.setSchema(olderTestSchema())
+
+	client := platform.newClient("test", ClientOptions{})
+	if err := client.Auth(context.Background(), "test"); err != nil {
+		t.Fatal(err)
+	}
+	n := NewNeedsData(client, "eks", []func() datasource.DataSource{NewPubSubSubscription})
+
+	// The schema is introspected by whichever read comes first, which
+	// may be canceled without affecting the other reads.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if schema, _ := n.capabilities(ctx); schema == nil {
+		t.Error("got no schema after a canceled read")
+	}
+}
+
+func TestBuildQueryMatchesClient(t *testing.T) {
+	vars := map[string]interface{}{
+		"appSlug": "app",
+		"envName": "env",
+		"types":   []TypeRef{"PubSubSubscription"},
+	}
+	queries := map[string]interface{}{
+		"needs":         &needsQuery{},
+		"introspection": &introspectionQuery{},
+	}
+	// The query of each kind of satisfier read by the data sources,
+	// on its own, to pinpoint the fragment that goes out of sync.
+	for i := 0; i < queryType.NumField(); i++ {
+		f := queryType.Field(i)
+		if !strings.HasPrefix(f.Tag.Get("graphql"), "... on ") {
+			continue
+		}
+		f.Anonymous = false
+		satisfier := reflect.StructOf([]reflect.StructField{queryType.Field(0), f})
+		query := reflect.StructOf([]reflect.StructField{{Name: "Satisfier", Type: satisfier, Tag: `graphql:"satisfier(name: $envName)"`}})
+		queries[f.Name] = reflect.New(query).Interface()
+	}
+
+	for name, q := range queries {
+		t.Run(name, func(t *testing.T) {
+			want, err := graphql.ConstructQuery(q, vars, graphql.OperationName(needsOperation))
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, _, err := buildQuery(q, nil, "", vars, graphql.OperationName(needsOperation))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != want {
+				t.Errorf("got query without schema\n%s\nwant\n%s", got, want)
+			}
+			schema, root := testSchema(q)
+			got, dropped, err := buildQuery(q, schema, root, vars, graphql.OperationName(needsOperation))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != want || len(dropped) > 0 {
+				t.Errorf("got query with full schema dropping %v\n%s\nwant\n%s", dropped, got, want)
+			}
+		})
+	}
+}
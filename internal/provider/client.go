@@ -4,13 +4,24 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"iter"
+	"maps"
 	"net/http"
+	"net/url"
 	"os"
 	"runtime"
+	"strconv"
+	"time"
 
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/hasura/go-graphql-client"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
 	"golang.org/x/oauth2"
 )
 
@@ -30,6 +41,12 @@ func (e Error) Error() string {
 	return fmt.Sprintf("http %s: code=%s", e.HTTPStatus, e.Code)
 }
 
+// IsNotFound reports whether err is a platform error for a missing resource.
+func IsNotFound(err error) bool {
+	var e Error
+	return errors.As(err, &e) && e.HTTPCode == http.StatusNotFound
+}
+
 type OAuthData struct {
 	Token   *oauth2.Token `json:"token"`
 	Actor   string        `json:"actor,omitempty"` // The ID of the user or app that authorized the token.
@@ -37,15 +54,39 @@ type OAuthData struct {
 	AppSlug string        `json:"app_slug"`        // empty if logging in as a user
 }
 
-func NewPlatformClient(version string) PlatformClient {
-	baseURL := os.Getenv("ENCORE_API_URL")
+// ClientOptions configures a PlatformClient.
+type ClientOptions struct {
+	// BaseURL is the URL of the platform API. It defaults to the
+	// ENCORE_API_URL env var, or DefaultBaseURL if unset.
+	BaseURL string
+	// HTTPClient is the client used for all requests to the platform,
+	// including token refreshes. It defaults to http.DefaultClient.
+	HTTPClient *http.Client
+	// TokenCacheDir is the directory to cache OAuth tokens in across
+	// provider invocations. Tokens are not cached if empty.
+	TokenCacheDir string
+}
+
+func NewPlatformClient(version string, opts ClientOptions) PlatformClient {
+	baseURL := opts.BaseURL
+	if baseURL == "" {
+		baseURL = os.Getenv("ENCORE_API_URL")
+	}
 	if baseURL == "" {
 		baseURL = DefaultBaseURL
 	}
+	baseClient := opts.HTTPClient
+	if baseClient == nil {
+		baseClient = http.DefaultClient
+	}
 	p := &PlatformClientImpl{
-		baseURL: baseURL,
-		version: version,
-		http:    http.DefaultClient,
+		baseURL:    baseURL,
+		version:    version,
+		baseClient: baseClient,
+		http:       baseClient,
+	}
+	if opts.TokenCacheDir != "" {
+		p.tokenCache = &tokenCache{dir: opts.TokenCacheDir}
 	}
 	p.gql = graphql.NewClient(baseURL+"/graphql", p)
 	return p
@@ -53,17 +94,109 @@ func NewPlatformClient(version string) PlatformClient {
 
 type PlatformClient interface {
 	Auth(ctx context.Context, authKey string) error
+	// AuthOIDC authenticates by exchanging an OIDC ID token issued by a
+	// trusted workload identity provider for the given audience.
+	AuthOIDC(ctx context.Context, idToken, audience string) error
+	// AuthToken authenticates with an already obtained OAuth token.
+	AuthToken(ctx context.Context, data *OAuthData) error
 	Call(ctx context.Context, method, path string, reqParams, respParams interface{}) error
 	GQL() *graphql.Client
 	AppSlug() string
+	// Token returns the OAuth token obtained by Auth, refreshing it if it has expired.
+	Token(ctx context.Context) (*oauth2.Token, error)
 }
 
 type PlatformClientImpl struct {
 	baseURL string
 	version string
 	appSlug string
+	tokens  oauth2.TokenSource
 	http    *http.Client
 	gql     *graphql.Client
+
+	// baseClient is the unauthenticated client that http wraps.
+	baseClient *http.Client
+
+	tokenCache *tokenCache
+}
+
+// appScopedClient is a PlatformClient operating on a given app, rather
+// than the app its credentials are scoped to.
+type appScopedClient struct {
+	PlatformClient
+	appSlug string
+}
+
+// withAppSlug returns a client operating on the app appSlug.
+func withAppSlug(client PlatformClient, appSlug string) PlatformClient {
+	return appScopedClient{PlatformClient: client, appSlug: appSlug}
+}
+
+func (c appScopedClient) AppSlug() string {
+	return c.appSlug
+}
+
+// credentialsAppSlug returns the slug of the app the credentials of client
+// are scoped to, or "" if they are a user's, regardless of the app the
+// client operates on.
+func credentialsAppSlug(client PlatformClient) string {
+	if c, ok := client.(appScopedClient); ok {
+		return c.PlatformClient.AppSlug()
+	}
+	return client.AppSlug()
+}
+
+// errConfigUnknown is returned by platform calls made while the provider
+// configuration is not yet known.
+var errConfigUnknown = errors.New("the provider configuration depends on values that are not known until apply; " +
+	"run Terraform with -allow-deferral, add the resources it depends on to depends_on, or apply them first with -target")
+
+// unknownConfigClient is a PlatformClient for a provider whose configuration
+// is unknown during plan. Resources that need no platform calls to plan
+// can be planned, refreshing them keeps their prior state, and the provider
+// is configured for real during apply. Data sources cannot be read, since
+// Terraform rejects unknown values read during plan.
+type unknownConfigClient struct {
+	appSlug string
+	gql     *graphql.Client
+}
+
+func newUnknownConfigClient(appSlug string) PlatformClient {
+	c := &unknownConfigClient{appSlug: appSlug}
+	c.gql = graphql.NewClient("unknown:///graphql", c)
+	return c
+}
+
+func (c *unknownConfigClient) Auth(ctx context.Context, authKey string) error {
+	return errConfigUnknown
+}
+
+func (c *unknownConfigClient) AuthOIDC(ctx context.Context, idToken, audience string) error {
+	return errConfigUnknown
+}
+
+func (c *unknownConfigClient) AuthToken(ctx context.Context, data *OAuthData) error {
+	return errConfigUnknown
+}
+
+func (c *unknownConfigClient) Call(ctx context.Context, method, path string, reqParams, respParams interface{}) error {
+	return errConfigUnknown
+}
+
+func (c *unknownConfigClient) Do(req *http.Request) (*http.Response, error) {
+	return nil, errConfigUnknown
+}
+
+func (c *unknownConfigClient) Token(ctx context.Context) (*oauth2.Token, error) {
+	return nil, errConfigUnknown
+}
+
+func (c *unknownConfigClient) GQL() *graphql.Client {
+	return c.gql
+}
+
+func (c *unknownConfigClient) AppSlug() string {
+	return c.appSlug
 }
 
 func (p *PlatformClientImpl) AppSlug() string {
@@ -75,21 +208,87 @@ func (p *PlatformClientImpl) GQL() *graphql.Client {
 }
 
 func (p *PlatformClientImpl) Auth(ctx context.Context, authKey string) error {
+	ctx = platformLogContext(ctx, authKey)
+	var cacheKey string
+	if p.tokenCache != nil {
+		cacheKey = p.tokenCache.key(authKey, p.baseURL)
+		if data := p.tokenCache.load(ctx, cacheKey); data != nil {
+			p.authToken(ctx, data, cacheKey)
+			// Check that the cached token is valid or can be refreshed.
+			_, err := p.tokens.Token()
+			if err == nil {
+				tflog.SubsystemDebug(ctx, logSubsystem, "using cached platform token")
+				return nil
+			}
+			tflog.SubsystemDebug(ctx, logSubsystem, "unable to refresh cached platform token, logging in", map[string]interface{}{"error": err.Error()})
+			p.tokens, p.http = nil, p.baseClient
+		}
+	}
+
 	var data OAuthData
 	err := p.Call(ctx, "POST", "/login/auth-key", struct {
 		AuthKey string `json:"auth_key"`
 	}{authKey}, &data)
 	if err != nil {
 		return err
+	} else if data.Token == nil {
+		return errors.New("missing oauth token")
+	}
+	tflog.SubsystemDebug(ctx, logSubsystem, "logged in with auth key", map[string]interface{}{
+		"app_slug":     data.AppSlug,
+		"actor":        data.Actor,
+		"token_expiry": data.Token.Expiry,
+	})
+	p.authToken(ctx, &data, cacheKey)
+	if cacheKey != "" {
+		p.tokenCache.store(ctx, cacheKey, &data)
 	}
+	return nil
+}
+
+func (p *PlatformClientImpl) AuthOIDC(ctx context.Context, idToken, audience string) error {
+	var data OAuthData
+	err := p.Call(ctx, "POST", "/login/oidc", struct {
+		IDToken  string `json:"id_token"`
+		Audience string `json:"audience"`
+	}{idToken, audience}, &data)
+	if err != nil {
+		return err
+	}
+	return p.AuthToken(ctx, &data)
+}
+
+func (p *PlatformClientImpl) AuthToken(ctx context.Context, data *OAuthData) error {
+	if data.Token == nil {
+		return errors.New("missing oauth token")
+	}
+	p.authToken(ctx, data, "")
+	return nil
+}
+
+// authToken authenticates with data, storing refreshed tokens in the
+// token cache under cacheKey if it is non-empty.
+func (p *PlatformClientImpl) authToken(ctx context.Context, data *OAuthData, cacheKey string) {
 	cfg := oauth2.Config{
 		Endpoint: oauth2.Endpoint{
 			TokenURL: p.baseURL + "/login/oauth:refresh-token",
 		},
 	}
+	// Use the base client for token refreshes and authenticated requests.
+	ctx = context.WithValue(ctx, oauth2.HTTPClient, p.baseClient)
 	p.appSlug = data.AppSlug
-	p.http = oauth2.NewClient(ctx, cfg.TokenSource(ctx, data.Token))
-	return nil
+	p.tokens = cfg.TokenSource(ctx, data.Token)
+	if cacheKey != "" {
+		p.tokens = &cachingTokenSource{ctx: ctx, src: p.tokens, cache: p.tokenCache, key: cacheKey, data: *data}
+	}
+	p.http = oauth2.NewClient(ctx, p.tokens)
+}
+
+func (p *PlatformClientImpl) Token(ctx context.Context) (*oauth2.Token, error) {
+	if p.tokens == nil {
+		return nil, errors.New("not authenticated")
+	}
+	return p.tokens.Token()
 }
 
 // Call makes a call to the API endpoint given by method and path.
@@ -115,7 +314,16 @@ func (p *PlatformClientImpl) Call(ctx context.Context, method, path string, reqP
 	if err != nil {
 		return err
 	}
-	return p.parsePlatformResp(resp, respParams)
+	err = p.parsePlatformResp(resp, respParams)
+	var platformErr Error
+	if errors.As(err, &platformErr) {
+		tflog.SubsystemDebug(platformLogContext(ctx), logSubsystem, "platform call failed", map[string]interface{}{
+			"method": method,
+			"path":   req.URL.Path,
+			"code":   platformErr.Code,
+		})
+	}
+	return err
 }
 
 func (p *PlatformClientImpl) parsePlatformResp(resp *http.Response, respParams interface{}) error {
@@ -145,10 +353,162 @@ func (p *PlatformClientImpl) parsePlatformResp(resp *http.Response, respParams i
 }
 
 func (p *PlatformClientImpl) Do(req *http.Request) (*http.Response, error) {
+	ctx, span := tracer.Start(req.Context(), "encore.platform "+req.Method+" "+req.URL.Path, trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("http.request.method", req.Method),
+			attribute.String("url.path", req.URL.Path),
+		))
+	defer span.End()
+	req = req.WithContext(ctx)
+	propagation.TraceContext{}.Inject(ctx, propagation.HeaderCarrier(req.Header))
+
 	// Add a very limited amount of information for diagnostics
 	req.Header.Set("User-Agent", "EncoreTF/"+p.version)
 	req.Header.Set("X-Encore-Version", p.version)
 	req.Header.Set("X-Encore-GOOS", runtime.GOOS)
 	req.Header.Set("X-Encore-GOARCH", runtime.GOARCH)
-	return p.http.Do(req)
+
+	ctx = platformLogContext(ctx)
+	fields := map[string]interface{}{
+		"method": req.Method,
+		"path":   req.URL.Path,
+	}
+	tflog.SubsystemTrace(ctx, logSubsystem, "sending platform request", fields)
+	start := time.Now()
+	var (
+		resp *http.Response
+		err  error
+	)
+	for retries := 0; ; retries++ {
+		resp, err = p.http.Do(req)
+		fields["retries"] = retries
+		if retries == maxRetries || !retryable(req, resp, err) {
+			break
+		}
+		if resp != nil {
+			_, _ = io.Copy(io.Discard, resp.Body)
+			_ = resp.Body.Close()
+		}
+		timer := time.NewTimer(retryBackoff << retries)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			resp, err = nil, ctx.Err()
+		case <-timer.C:
+			continue
+		}
+		break
+	}
+	fields["duration_ms"] = time.Since(start).Milliseconds()
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		fields["error"] = err.Error()
+		tflog.SubsystemDebug(ctx, logSubsystem, "platform request failed", fields)
+		return nil, err
+	}
+	span.SetAttributes(attribute.Int("http.response.status_code", resp.StatusCode))
+	if resp.StatusCode >= 400 {
+		span.SetStatus(codes.Error, resp.Status)
+	}
+	fields["status"] = resp.StatusCode
+	// The response is logged once its body is closed, to include
+	// the size of chunked responses that have no Content-Length.
+	resp.Body = &loggedBody{ReadCloser: resp.Body, ctx: ctx, fields: fields}
+	return resp, nil
+}
+
+// maxRetries is the number of times idempotent platform requests are
+// retried on transient errors.
+const maxRetries = 2
+
+// retryBackoff is the delay before the first retry of a platform request,
+// which doubles with every further retry. It is overridden in tests.
+var retryBackoff = 250 * time.Millisecond
+
+// retryable reports whether req is worth retrying after it got resp or
+// failed with err. Only requests without side effects are retried, on
+// network errors and on responses that signal a temporary outage.
+func retryable(req *http.Request, resp *http.Response, err error) bool {
+	if req.Method != http.MethodGet && req.Method != http.MethodHead {
+		return false
+	}
+	if err != nil {
+		return req.Context().Err() == nil
+	}
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// loggedBody is the body of a platform response, which logs the
+// response along with the number of bytes read from it when closed.
+type loggedBody struct {
+	io.ReadCloser
+	ctx    context.Context
+	fields map[string]interface{}
+	size   int64
+	closed bool
+}
+
+func (b *loggedBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.size += int64(n)
+	return n, err
+}
+
+func (b *loggedBody) Close() error {
+	if !b.closed {
+		b.closed = true
+		b.fields["response_size"] = b.size
+		tflog.SubsystemDebug(b.ctx, logSubsystem, "platform request", b.fields)
+	}
+	return b.ReadCloser.Close()
+}
+
+// Page is a page of results from a paginated platform API endpoint.
+type Page[T any] struct {
+	Items         []T    `json:"items"`
+	NextPageToken string `json:"next_page_token"`
+}
+
+// paginate iterates over all items of the paginated endpoint at path,
+// fetching pages of up to pageSize items as needed. Iteration stops at
+// the first error, which is yielded along with the zero value of T.
+func paginate[T any](ctx context.Context, client PlatformClient, path string, query url.Values, pageSize int) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		query := maps.Clone(query)
+		if query == nil {
+			query = url.Values{}
+		}
+		query.Set("limit", strconv.Itoa(pageSize))
+		for {
+			var page Page[T]
+			if err := client.Call(ctx, "GET", path+"?"+query.Encode(), nil, &page); err != nil {
+				var zero T
+				yield(zero, err)
+				return
+			}
+			for _, item := range page.Items {
+				if !yield(item, nil) {
+					return
+				}
+			}
+			if page.NextPageToken == "" {
+				return
+			}
+			query.Set("page_token", page.NextPageToken)
+		}
+	}
+}
+
+// escapef formats a platform API path, escaping each argument as a path segment.
+func escapef(format string, args ...string) string {
+	ifaces := make([]interface{}, len(args))
+	for i, arg := range args {
+		ifaces[i] = url.PathEscape(arg)
+	}
+	return fmt.Sprintf(format, ifaces...)
 }
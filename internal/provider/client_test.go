@@ -0,0 +1,59 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestPlatformClientToken(t *testing.T) {
+	expiry := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var params struct {
+			AuthKey string `json:"auth_key"`
+		}
+		if r.URL.Path != "/login/auth-key" || json.NewDecoder(r.Body).Decode(&params) != nil || params.AuthKey != "my-key" {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"ok":false,"error":{"code":"unauthorized"}}`))
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"ok": true,
+			"data": map[string]interface{}{
+				"token": map[string]interface{}{
+					"access_token": "access",
+					"token_type":   "Bearer",
+					"expiry":       expiry,
+				},
+				"app_slug": "my-app",
+			},
+		})
+	}))
+	defer srv.Close()
+	t.Setenv("ENCORE_API_URL", srv.URL)
+
+	ctx := context.Background()
+	client := NewPlatformClient("test", ClientOptions{})
+	if _, err := client.Token(ctx); err == nil {
+		t.Fatal("expected error before authenticating")
+	}
+	if err := client.Auth(ctx, "wrong-key"); err == nil {
+		t.Fatal("expected error for wrong auth key")
+	}
+	if err := client.Auth(ctx, "my-key"); err != nil {
+		t.Fatal(err)
+	}
+	token, err := client.Token(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if token.AccessToken != "access" || token.Type() != "Bearer" || !token.Expiry.Equal(expiry) {
+		t.Errorf("got token %+v", token)
+	}
+	if client.AppSlug() != "my-app" {
+		t.Errorf("got app slug %q, want %q", client.AppSlug(), "my-app")
+	}
+}
@@ -0,0 +1,278 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"golang.org/x/oauth2"
+)
+
+// credentialSource is a step of the provider credential chain.
+type credentialSource struct {
+	// name describes the source in diagnostics.
+	name string
+	// attr is the provider attribute the source is configured with,
+	// or empty if it is configured outside of the provider configuration.
+	attr path.Path
+	// login authenticates client with the credentials of the source.
+	// It returns a non-empty skip reason if the source is not configured.
+	login func(ctx context.Context, client PlatformClient) (skip string, err error)
+}
+
+// credentialChain returns the credential sources of the provider
+// configuration, in order of precedence.
+func credentialChain(data EncoreProviderModel) []credentialSource {
+	return []credentialSource{
+		{
+			name: "auth_key",
+			attr: path.Root("auth_key"),
+			login: func(ctx context.Context, client PlatformClient) (string, error) {
+				if data.APIKey.ValueString() == "" {
+					return "not set", nil
+				}
+				return "", client.Auth(ctx, data.APIKey.ValueString())
+			},
+		},
+		{
+			name: "auth_key_file",
+			attr: path.Root("auth_key_file"),
+			login: func(ctx context.Context, client PlatformClient) (string, error) {
+				if data.APIKeyFile.ValueString() == "" {
+					return "not set", nil
+				}
+				key, err := readCredentialFile(data.APIKeyFile.ValueString())
+				if err != nil {
+					return "", err
+				}
+				return "", client.Auth(ctx, key)
+			},
+		},
+		{
+			name: "oidc_token",
+			attr: path.Root("oidc_token"),
+			login: func(ctx context.Context, client PlatformClient) (string, error) {
+				if data.OIDCToken.ValueString() == "" {
+					return "not set", nil
+				}
+				return "", client.AuthOIDC(ctx, data.OIDCToken.ValueString(), oidcAudience(data))
+			},
+		},
+		{
+			name: "oidc_token_file",
+			attr: path.Root("oidc_token_file"),
+			login: func(ctx context.Context, client PlatformClient) (string, error) {
+				if data.OIDCTokenFile.ValueString() == "" {
+					return "not set", nil
+				}
+				token, err := readCredentialFile(data.OIDCTokenFile.ValueString())
+				if err != nil {
+					return "", err
+				}
+				return "", client.AuthOIDC(ctx, token, oidcAudience(data))
+			},
+		},
+		{
+			name: "ENCORE_AUTH_KEY",
+			login: func(ctx context.Context, client PlatformClient) (string, error) {
+				key := os.Getenv("ENCORE_AUTH_KEY")
+				if key == "" {
+					return "environment variable not set", nil
+				}
+				return "", client.Auth(ctx, key)
+			},
+		},
+		{
+			name: "TFC_WORKLOAD_IDENTITY_TOKEN",
+			login: func(ctx context.Context, client PlatformClient) (string, error) {
+				token := os.Getenv("TFC_WORKLOAD_IDENTITY_TOKEN")
+				if token == "" {
+					return "environment variable not set", nil
+				}
+				return "", client.AuthOIDC(ctx, token, oidcAudience(data))
+			},
+		},
+		{
+			name: "GitHub Actions OIDC",
+			login: func(ctx context.Context, client PlatformClient) (string, error) {
+				reqURL, reqToken := os.Getenv("ACTIONS_ID_TOKEN_REQUEST_URL"), os.Getenv("ACTIONS_ID_TOKEN_REQUEST_TOKEN")
+				if reqURL == "" || reqToken == "" {
+					return "not running in GitHub Actions with the id-token permission", nil
+				}
+				token, err := githubActionsIDToken(ctx, reqURL, reqToken, oidcAudience(data))
+				if err != nil {
+					return "", err
+				}
+				return "", client.AuthOIDC(ctx, token, oidcAudience(data))
+			},
+		},
+		{
+			name: "Encore CLI login",
+			login: func(ctx context.Context, client PlatformClient) (string, error) {
+				data, skip, err := cliOAuthData()
+				if skip != "" || err != nil {
+					return skip, err
+				}
+				return "", client.AuthToken(ctx, data)
+			},
+		},
+	}
+}
+
+// authenticate logs client in with the first configured credential source.
+// A configured source that fails is an error rather than falling through
+// to the next source, so that the credentials in use are always the ones
+// with the highest precedence.
+func authenticate(ctx context.Context, client PlatformClient, sources []credentialSource) (diags diag.Diagnostics) {
+	var skipped []string
+	for _, src := range sources {
+		skip, err := src.login(ctx, client)
+		if err != nil {
+			summary := "Authentication Failed"
+			detail := fmt.Sprintf("Failed to authenticate with the Encore Platform using %s: %s", src.name, err)
+			if len(src.attr.Steps()) > 0 {
+				diags.AddAttributeError(src.attr, summary, detail)
+			} else {
+				diags.AddError(summary, detail)
+			}
+			return diags
+		} else if skip != "" {
+			tflog.Debug(ctx, "skipped credential source", map[string]interface{}{"source": src.name, "reason": skip})
+			skipped = append(skipped, fmt.Sprintf("- %s: %s", src.name, skip))
+			continue
+		}
+		tflog.Info(ctx, "authenticated with the Encore Platform", map[string]interface{}{"source": src.name})
+		if len(src.attr.Steps()) == 0 {
+			// Credentials from outside the provider configuration are
+			// easy to pick up by accident, so name them.
+			diags.AddWarning(fmt.Sprintf("Using %s for Authentication", src.name),
+				fmt.Sprintf("Authenticated with the Encore Platform using %s, since no credentials are set in the provider configuration. Checked, in order:\n\n%s",
+					src.name, strings.Join(skipped, "\n")))
+		}
+		return diags
+	}
+	diags.AddError("No valid credential sources found",
+		"Failed to find credentials for the Encore Platform. Checked, in order:\n\n"+strings.Join(skipped, "\n"))
+	return diags
+}
+
+// readCredentialFile reads a credential from the file fn, ignoring
+// surrounding whitespace.
+func readCredentialFile(fn string) (string, error) {
+	b, err := os.ReadFile(fn)
+	if err != nil {
+		return "", err
+	}
+	cred := strings.TrimSpace(string(b))
+	if cred == "" {
+		return "", fmt.Errorf("%s is empty", fn)
+	}
+	return cred, nil
+}
+
+// DefaultOIDCAudience is the audience of OIDC tokens requested for the Encore Platform.
+const DefaultOIDCAudience = "encore.dev"
+
+// oidcAudience returns the audience of the OIDC tokens of the provider
+// configuration, which the platform validates them against.
+func oidcAudience(data EncoreProviderModel) string {
+	if audience := data.OIDCAudience.ValueString(); audience != "" {
+		return audience
+	}
+	return DefaultOIDCAudience
+}
+
+// githubActionsIDToken requests an OIDC ID token for audience from the
+// GitHub Actions token endpoint.
+func githubActionsIDToken(ctx context.Context, reqURL, reqToken, audience string) (string, error) {
+	u, err := url.Parse(reqURL)
+	if err != nil {
+		return "", fmt.Errorf("parse ACTIONS_ID_TOKEN_REQUEST_URL: %v", err)
+	}
+	q := u.Query()
+	q.Set("audience", audience)
+	u.RawQuery = q.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, "GET", u.String(), nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Authorization", "Bearer "+reqToken)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("request ID token: %v", err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("request ID token: http %s", resp.Status)
+	}
+	var respData struct {
+		Value string `json:"value"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&respData); err != nil {
+		return "", fmt.Errorf("decode ID token response: %v", err)
+	} else if respData.Value == "" {
+		return "", errors.New("empty ID token response")
+	}
+	return respData.Value, nil
+}
+
+// cliAuthConfig is the login state stored by the Encore CLI.
+type cliAuthConfig struct {
+	oauth2.Token
+	Actor   string `json:"actor,omitempty"`
+	Email   string `json:"email,omitempty"`
+	AppSlug string `json:"app_slug,omitempty"`
+}
+
+// cliConfigDir returns the configuration directory of the Encore CLI.
+func cliConfigDir() (string, error) {
+	if dir := os.Getenv("ENCORE_CONFIG_DIR"); dir != "" {
+		return dir, nil
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "encore"), nil
+}
+
+// cliOAuthData reads the OAuth token stored by `encore auth login`.
+// User logins are not scoped to an app, which must then be configured
+// with the `app` attribute.
+func cliOAuthData() (data *OAuthData, skip string, err error) {
+	dir, err := cliConfigDir()
+	if err != nil {
+		return nil, "unable to find the Encore CLI config directory: " + err.Error(), nil
+	}
+	fn := filepath.Join(dir, ".auth_token")
+	b, err := os.ReadFile(fn)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Sprintf("not logged in (%s does not exist)", fn), nil
+	} else if err != nil {
+		return nil, "", err
+	}
+
+	var cfg cliAuthConfig
+	if err := json.Unmarshal(b, &cfg); err != nil {
+		return nil, "", fmt.Errorf("parse %s: %v", fn, err)
+	}
+	return &OAuthData{
+		Token:   &cfg.Token,
+		Actor:   cfg.Actor,
+		Email:   cfg.Email,
+		AppSlug: cfg.AppSlug,
+	}, "", nil
+}
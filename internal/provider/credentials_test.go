@@ -0,0 +1,238 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// testCredentialEnvVars are the environment variables read by the
+// credential chain, which are cleared for each test.
+var testCredentialEnvVars = []string{
+	"ENCORE_AUTH_KEY",
+	"TFC_WORKLOAD_IDENTITY_TOKEN",
+	"ACTIONS_ID_TOKEN_REQUEST_URL",
+	"ACTIONS_ID_TOKEN_REQUEST_TOKEN",
+}
+
+func TestCredentialChain(t *testing.T) {
+	dir := t.TempDir()
+	keyFile := filepath.Join(dir, "auth-key")
+	if err := os.WriteFile(keyFile, []byte("file-key\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	cliDir := filepath.Join(dir, "encore")
+	if err := os.Mkdir(cliDir, 0o700); err != nil {
+		t.Fatal(err)
+	}
+	writeCLIToken := func(token string) {
+		if err := os.WriteFile(filepath.Join(cliDir, ".auth_token"), []byte(token), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name      string
+		config    EncoreProviderModel
+		env       map[string]string
+		cliToken  string
+		wantLogin string
+		wantWarn  string
+		wantErr   []string
+		wantPath  string
+	}{
+		{
+			name:      "auth_key takes precedence",
+			config:    EncoreProviderModel{APIKey: types.StringValue("config-key"), APIKeyFile: types.StringValue(keyFile)},
+			env:       map[string]string{"ENCORE_AUTH_KEY": "env-key"},
+			wantLogin: "auth_key:config-key",
+		},
+		{
+			name:      "auth_key_file",
+			config:    EncoreProviderModel{APIKeyFile: types.StringValue(keyFile)},
+			env:       map[string]string{"ENCORE_AUTH_KEY": "env-key"},
+			wantLogin: "auth_key:file-key",
+		},
+		{
+			name:      "env var",
+			env:       map[string]string{"ENCORE_AUTH_KEY": "env-key"},
+			cliToken:  `{"access_token": "cli-token", "app_slug": "my-app"}`,
+			wantLogin: "auth_key:env-key",
+			wantWarn:  "Using ENCORE_AUTH_KEY for Authentication",
+		},
+		{
+			name:      "cli login",
+			cliToken:  `{"access_token": "cli-token", "token_type": "Bearer", "actor": "a_123", "app_slug": "my-app"}`,
+			wantLogin: "token:cli-token",
+			wantWarn:  "Using Encore CLI login for Authentication",
+		},
+		{
+			name:      "oidc_token takes precedence over env vars",
+			config:    EncoreProviderModel{OIDCToken: types.StringValue("config-id-token")},
+			env:       map[string]string{"ENCORE_AUTH_KEY": "env-key"},
+			wantLogin: "oidc:config-id-token@encore.dev",
+		},
+		{
+			name:      "oidc_token_file",
+			config:    EncoreProviderModel{OIDCTokenFile: types.StringValue(keyFile)},
+			wantLogin: "oidc:file-key@encore.dev",
+		},
+		{
+			name:      "HCP Terraform workload identity",
+			config:    EncoreProviderModel{OIDCAudience: types.StringValue("app.terraform.io")},
+			env:       map[string]string{"TFC_WORKLOAD_IDENTITY_TOKEN": "tfc-id-token"},
+			cliToken:  `{"access_token": "cli-token", "app_slug": "my-app"}`,
+			wantLogin: "oidc:tfc-id-token@app.terraform.io",
+			wantWarn:  "Using TFC_WORKLOAD_IDENTITY_TOKEN for Authentication",
+		},
+		{
+			name:      "cli user login",
+			cliToken:  `{"access_token": "cli-token", "email": "jane@example.com"}`,
+			wantLogin: "token:cli-token",
+			wantWarn:  "Using Encore CLI login for Authentication",
+		},
+		{
+			name: "no credentials",
+			wantErr: []string{
+				"No valid credential sources found",
+				"- auth_key: not set",
+				"- auth_key_file: not set",
+				"- oidc_token: not set",
+				"- oidc_token_file: not set",
+				"- ENCORE_AUTH_KEY: environment variable not set",
+				"- TFC_WORKLOAD_IDENTITY_TOKEN: environment variable not set",
+				"- GitHub Actions OIDC: not running in GitHub Actions",
+				"- Encore CLI login: not logged in",
+			},
+		},
+		{
+			name:     "invalid auth_key does not fall through",
+			config:   EncoreProviderModel{APIKey: types.StringValue("invalid-key")},
+			env:      map[string]string{"ENCORE_AUTH_KEY": "env-key"},
+			wantErr:  []string{"Authentication Failed", "using auth_key: http 401 Unauthorized"},
+			wantPath: "auth_key",
+		},
+		{
+			name:    "invalid ENCORE_AUTH_KEY",
+			env:     map[string]string{"ENCORE_AUTH_KEY": "invalid-key"},
+			wantErr: []string{"using ENCORE_AUTH_KEY: http 401 Unauthorized"},
+		},
+		{
+			name:     "missing auth_key_file",
+			config:   EncoreProviderModel{APIKeyFile: types.StringValue(filepath.Join(dir, "missing"))},
+			wantErr:  []string{"using auth_key_file: open"},
+			wantPath: "auth_key_file",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, name := range testCredentialEnvVars {
+				t.Setenv(name, tt.env[name])
+			}
+			t.Setenv("ENCORE_CONFIG_DIR", cliDir)
+			_ = os.Remove(filepath.Join(cliDir, ".auth_token"))
+			if tt.cliToken != "" {
+				writeCLIToken(tt.cliToken)
+			}
+			testPlatform.mu.Lock()
+			testPlatform.logins = nil
+			testPlatform.mu.Unlock()
+
+			diags := authenticate(context.Background(), newTestPlatformClient("test", ClientOptions{}), credentialChain(tt.config))
+			if len(tt.wantErr) > 0 {
+				if !diags.HasError() {
+					t.Fatal("expected error")
+				}
+				msg := diags.Errors()[0].Summary() + ": " + diags.Errors()[0].Detail()
+				for _, want := range tt.wantErr {
+					if !strings.Contains(msg, want) {
+						t.Errorf("got error %q, want it to contain %q", msg, want)
+					}
+				}
+				var gotPath string
+				if d, ok := diags.Errors()[0].(diag.DiagnosticWithPath); ok {
+					gotPath = d.Path().String()
+				}
+				if gotPath != tt.wantPath {
+					t.Errorf("got error for attribute %q, want %q", gotPath, tt.wantPath)
+				}
+				return
+			}
+			if diags.HasError() {
+				t.Fatal(diags)
+			}
+			var gotWarn string
+			if warns := diags.Warnings(); len(warns) > 0 {
+				gotWarn = warns[0].Summary()
+			}
+			if gotWarn != tt.wantWarn {
+				t.Errorf("got warning %q, want %q", gotWarn, tt.wantWarn)
+			}
+			if got := strings.Join(testPlatform.logins, ","); got != tt.wantLogin {
+				t.Errorf("got logins %q, want %q", got, tt.wantLogin)
+			}
+		})
+	}
+}
+
+// TestOIDCLogin logs in through a stand-in GitHub Actions token issuer
+// and a stand-in platform that only accepts ID tokens for its audience.
+func TestOIDCLogin(t *testing.T) {
+	issuer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer request-token" || r.URL.Query().Get("api-version") != "2.0" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]string{"value": "id-token-for-" + r.URL.Query().Get("audience")})
+	}))
+	defer issuer.Close()
+	platform := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var params struct {
+			IDToken  string `json:"id_token"`
+			Audience string `json:"audience"`
+		}
+		if r.URL.Path != "/login/oidc" || json.NewDecoder(r.Body).Decode(&params) != nil ||
+			params.IDToken != "id-token-for-"+params.Audience || params.Audience != "my-audience" {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"ok":false,"error":{"code":"unauthenticated"}}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"ok":true,"data":{"token":{"access_token":"platform-token","token_type":"Bearer"},"app_slug":"my-app"}}`))
+	}))
+	defer platform.Close()
+
+	for _, name := range testCredentialEnvVars {
+		t.Setenv(name, "")
+	}
+	t.Setenv("ENCORE_CONFIG_DIR", t.TempDir())
+	t.Setenv("ENCORE_API_URL", platform.URL)
+	t.Setenv("ACTIONS_ID_TOKEN_REQUEST_URL", issuer.URL+"?api-version=2.0")
+	t.Setenv("ACTIONS_ID_TOKEN_REQUEST_TOKEN", "request-token")
+
+	ctx := context.Background()
+	client := NewPlatformClient("test", ClientOptions{})
+	diags := authenticate(ctx, client, credentialChain(EncoreProviderModel{}))
+	if !diags.HasError() || !strings.Contains(diags.Errors()[0].Detail(), "using GitHub Actions OIDC: http 401") {
+		t.Fatalf("expected login with the default audience to fail, got %v", diags)
+	}
+
+	diags = authenticate(ctx, client, credentialChain(EncoreProviderModel{OIDCAudience: types.StringValue("my-audience")}))
+	if diags.HasError() {
+		t.Fatal(diags)
+	}
+	token, err := client.Token(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if token.AccessToken != "platform-token" || client.AppSlug() != "my-app" {
+		t.Errorf("got token %q for app %q", token.AccessToken, client.AppSlug())
+	}
+}
@@ -0,0 +1,68 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/list"
+	"github.com/hashicorp/terraform-plugin-framework/list/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var _ list.ListResourceWithConfigure = &CustomDomainListResource{}
+
+func NewCustomDomainList() list.ListResource {
+	return &CustomDomainListResource{}
+}
+
+// CustomDomainListResource lists the custom domains of an environment.
+// It shares Metadata and Configure with CustomDomainResource.
+type CustomDomainListResource struct {
+	CustomDomainResource
+}
+
+// CustomDomainListResourceModel describes the list resource config data model.
+type CustomDomainListResourceModel struct {
+	Env types.String `tfsdk:"env"`
+}
+
+func (r *CustomDomainListResource) ListResourceConfigSchema(ctx context.Context, req list.ListResourceSchemaRequest, resp *list.ListResourceSchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Lists the custom domains attached to the API gateway of an Encore environment.",
+		Attributes: map[string]schema.Attribute{
+			"env": schema.StringAttribute{
+				MarkdownDescription: "The environment to list the custom domains of. Defaults to the provider environment",
+				Optional:            true,
+			},
+		},
+	}
+}
+
+func (r *CustomDomainListResource) List(ctx context.Context, req list.ListRequest, stream *list.ListResultsStream) {
+	var config CustomDomainListResourceModel
+	diags := req.Config.Get(ctx, &config)
+	diags.Append(checkApp(r.client)...)
+	if diags.HasError() {
+		stream.Results = list.ListResultsStreamDiagnostics(diags)
+		return
+	}
+	envName := config.Env.ValueString()
+	if envName == "" {
+		envName = r.defaultEnv
+	}
+
+	domains := paginate[*CustomDomain](ctx, r.client, r.path(envName), nil, listPageSize(req))
+	stream.Results = listResults(ctx, req, domains, "custom domains", func(domain *CustomDomain, result *list.ListResult) {
+		data := CustomDomainResourceModel{
+			Env:                types.StringValue(envName),
+			WaitForCertificate: types.BoolValue(false),
+			PathPrefix:         types.StringNull(),
+			Timeouts:           nullTimeouts("create", "update"),
+		}
+		result.Diagnostics.Append(data.set(ctx, domain)...)
+		result.DisplayName = domain.Hostname
+		result.Diagnostics.Append(result.Identity.Set(ctx, data.identity())...)
+		if req.IncludeResource {
+			result.Diagnostics.Append(result.Resource.Set(ctx, &data)...)
+		}
+	})
+}
@@ -0,0 +1,81 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/querycheck"
+	"github.com/hashicorp/terraform-plugin-testing/querycheck/queryfilter"
+	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
+	"github.com/hashicorp/terraform-plugin-testing/tfversion"
+)
+
+func TestCustomDomainListResource(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testV6ProviderFactories,
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_14_0),
+		},
+		CheckDestroy: testCustomDomainDestroyed,
+		Steps: []resource.TestStep{
+			{
+				Config: testCustomDomainListResourceConfig,
+			},
+			{
+				Query: true,
+				// The provider is configured by the previous step's configuration.
+				Config: `
+list "encore_custom_domain" "all" {
+	provider         = encore
+	include_resource = true
+}
+`,
+				QueryResultChecks: []querycheck.QueryResultCheck{
+					querycheck.ExpectLength("encore_custom_domain.all", 3),
+					querycheck.ExpectIdentity("encore_custom_domain.all", map[string]knownvalue.Check{
+						"env":      knownvalue.StringExact("domain-list"),
+						"hostname": knownvalue.StringExact("c.example.com"),
+					}),
+					querycheck.ExpectResourceDisplayName("encore_custom_domain.all", queryfilter.ByResourceIdentity(map[string]knownvalue.Check{
+						"env":      knownvalue.StringExact("domain-list"),
+						"hostname": knownvalue.StringExact("a.example.com"),
+					}), knownvalue.StringExact("a.example.com")),
+					querycheck.ExpectResourceKnownValues("encore_custom_domain.all", queryfilter.ByResourceIdentity(map[string]knownvalue.Check{
+						"env":      knownvalue.StringExact("domain-list"),
+						"hostname": knownvalue.StringExact("b.example.com"),
+					}), []querycheck.KnownValueCheck{
+						{Path: tfjsonpath.New("id"), KnownValue: knownvalue.StringExact("domain-list/b.example.com")},
+						{Path: tfjsonpath.New("target_records").AtSliceIndex(0).AtMapKey("value"), KnownValue: knownvalue.StringExact("domain-list.gateway.encr.app")},
+					}),
+				},
+			},
+			{
+				Query: true,
+				Config: `
+list "encore_custom_domain" "other" {
+	provider = encore
+	config {
+		env = "other"
+	}
+}
+`,
+				QueryResultChecks: []querycheck.QueryResultCheck{
+					querycheck.ExpectLength("encore_custom_domain.other", 0),
+				},
+			},
+		},
+	})
+}
+
+const testCustomDomainListResourceConfig = `
+provider "encore" {
+	auth_key = "test"
+	env = "domain-list"
+}
+
+resource "encore_custom_domain" "domain" {
+	count    = 3
+	hostname = "${["a", "b", "c"][count.index]}.example.com"
+}
+`
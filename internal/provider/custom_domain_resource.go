@@ -0,0 +1,394 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/identityschema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+const defaultCustomDomainCreateTimeout = 30 * time.Minute
+
+var (
+	_ resource.Resource                = &CustomDomainResource{}
+	_ resource.ResourceWithConfigure   = &CustomDomainResource{}
+	_ resource.ResourceWithImportState = &CustomDomainResource{}
+	_ resource.ResourceWithIdentity    = &CustomDomainResource{}
+)
+
+func NewCustomDomain() resource.Resource {
+	return &CustomDomainResource{}
+}
+
+// CustomDomain is a custom domain as returned by the Encore Platform.
+type CustomDomain struct {
+	Hostname          string      `json:"hostname"`
+	PathPrefix        string      `json:"path_prefix"`
+	CertificateStatus string      `json:"certificate_status"`
+	ValidationRecords []DNSRecord `json:"validation_records"`
+	TargetRecords     []DNSRecord `json:"target_records"`
+}
+
+const (
+	CertificatePending = "pending"
+	CertificateIssued  = "issued"
+	CertificateFailed  = "failed"
+)
+
+type DNSRecord struct {
+	Name  string `json:"name" tfsdk:"name"`
+	Type  string `json:"type" tfsdk:"type"`
+	Value string `json:"value" tfsdk:"value"`
+}
+
+var dnsRecordType = types.ObjectType{AttrTypes: map[string]attr.Type{
+	"name":  types.StringType,
+	"type":  types.StringType,
+	"value": types.StringType,
+}}
+
+type CustomDomainResource struct {
+	client     PlatformClient
+	defaultEnv string
+}
+
+// CustomDomainResourceModel describes the resource data model.
+type CustomDomainResourceModel struct {
+	ID                 types.String   `tfsdk:"id"`
+	Env                types.String   `tfsdk:"env"`
+	Hostname           types.String   `tfsdk:"hostname"`
+	PathPrefix         types.String   `tfsdk:"path_prefix"`
+	WaitForCertificate types.Bool     `tfsdk:"wait_for_certificate"`
+	CertificateStatus  types.String   `tfsdk:"certificate_status"`
+	ValidationRecords  types.List     `tfsdk:"validation_records"`
+	TargetRecords      types.List     `tfsdk:"target_records"`
+	Timeouts           timeouts.Value `tfsdk:"timeouts"`
+}
+
+// CustomDomainIdentityModel describes the resource identity data model.
+type CustomDomainIdentityModel struct {
+	Env      types.String `tfsdk:"env"`
+	Hostname types.String `tfsdk:"hostname"`
+}
+
+func (r *CustomDomainResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_custom_domain"
+}
+
+func (r *CustomDomainResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	dnsRecordAttrs := map[string]schema.Attribute{
+		"name": schema.StringAttribute{
+			MarkdownDescription: "The fully qualified name of the DNS record",
+			Computed:            true,
+		},
+		"type": schema.StringAttribute{
+			MarkdownDescription: "The type of the DNS record, e.g. `CNAME`",
+			Computed:            true,
+		},
+		"value": schema.StringAttribute{
+			MarkdownDescription: "The value of the DNS record",
+			Computed:            true,
+		},
+	}
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "A custom domain attached to the API gateway of an Encore environment.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				MarkdownDescription: "The id of the custom domain in the form of `{env}/{hostname}`",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"env": schema.StringAttribute{
+				MarkdownDescription: "The environment to attach the domain to. Defaults to the provider environment",
+				Optional:            true,
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"hostname": schema.StringAttribute{
+				MarkdownDescription: "The hostname of the custom domain, e.g. `api.example.com`",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"path_prefix": schema.StringAttribute{
+				MarkdownDescription: "Only route requests with this path prefix to the gateway. Defaults to routing all requests",
+				Optional:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"wait_for_certificate": schema.BoolAttribute{
+				MarkdownDescription: "Wait until the TLS certificate for the domain has been issued before completing creation, or the update enabling it. " +
+					"The certificate can only be issued once the `validation_records` and `target_records` exist, " +
+					"so only enable this if the DNS records are managed outside of this configuration. Defaults to `false`",
+				Optional: true,
+				Computed: true,
+				Default:  booldefault.StaticBool(false),
+			},
+			"certificate_status": schema.StringAttribute{
+				MarkdownDescription: "The status of the TLS certificate for the domain. One of `pending`, `issued` or `failed`",
+				Computed:            true,
+			},
+			"validation_records": schema.ListNestedAttribute{
+				MarkdownDescription: "The DNS records that must be created to validate ownership of the domain",
+				Computed:            true,
+				NestedObject:        schema.NestedAttributeObject{Attributes: dnsRecordAttrs},
+			},
+			"target_records": schema.ListNestedAttribute{
+				MarkdownDescription: "The DNS records that must be created to route traffic for the domain to the gateway",
+				Computed:            true,
+				NestedObject:        schema.NestedAttributeObject{Attributes: dnsRecordAttrs},
+			},
+			"timeouts": timeouts.Attributes(ctx, timeouts.Opts{
+				Create: true,
+				Update: true,
+			}),
+		},
+	}
+}
+
+func (r *CustomDomainResource) IdentitySchema(ctx context.Context, req resource.IdentitySchemaRequest, resp *resource.IdentitySchemaResponse) {
+	resp.IdentitySchema = identityschema.Schema{
+		Attributes: map[string]identityschema.Attribute{
+			"env": identityschema.StringAttribute{
+				Description:       "The environment the domain is attached to",
+				RequiredForImport: true,
+			},
+			"hostname": identityschema.StringAttribute{
+				Description:       "The hostname of the custom domain",
+				RequiredForImport: true,
+			},
+		},
+	}
+}
+
+func (r *CustomDomainResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	needs, ok := req.ProviderData.(*NeedsData)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *NeedsData, received %T", req.ProviderData),
+		)
+		return
+	}
+
+	r.client = needs.client
+	r.defaultEnv = needs.defaultEnv
+}
+
+func (r *CustomDomainResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data CustomDomainResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	resp.Diagnostics.Append(checkApp(r.client)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if data.Env.ValueString() == "" {
+		data.Env = types.StringValue(r.defaultEnv)
+	}
+
+	var domain CustomDomain
+	err := r.client.Call(ctx, "POST", r.path(data.Env.ValueString()), struct {
+		Hostname   string `json:"hostname"`
+		PathPrefix string `json:"path_prefix,omitempty"`
+	}{data.Hostname.ValueString(), data.PathPrefix.ValueString()}, &domain)
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to create custom domain, got error: %s", err))
+		return
+	}
+
+	if data.WaitForCertificate.ValueBool() && domain.CertificateStatus != CertificateIssued {
+		createTimeout, diags := data.Timeouts.Create(ctx, defaultCustomDomainCreateTimeout)
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		if err := r.waitForCertificate(ctx, data.Env.ValueString(), data.Hostname.ValueString(), &domain, createTimeout); err != nil {
+			// Keep the domain in state so that it is tainted rather than orphaned.
+			resp.Diagnostics.Append(data.set(ctx, &domain)...)
+			resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+			resp.Diagnostics.Append(resp.Identity.Set(ctx, data.identity())...)
+			resp.Diagnostics.AddError("Certificate Error", fmt.Sprintf("Custom domain %s was created, but its certificate was not issued: %s", data.Hostname.ValueString(), err))
+			return
+		}
+	}
+
+	resp.Diagnostics.Append(data.set(ctx, &domain)...)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, data.identity())...)
+}
+
+func (r *CustomDomainResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data CustomDomainResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	resp.Diagnostics.Append(checkApp(r.client)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var domain CustomDomain
+	err := r.client.Call(ctx, "GET", r.path(data.Env.ValueString(), data.Hostname.ValueString()), nil, &domain)
+	if IsNotFound(err) {
+		resp.State.RemoveResource(ctx)
+		return
+	} else if errors.Is(err, errConfigUnknown) {
+		// Keep the prior state until the provider can authenticate during apply.
+		resp.Diagnostics.Append(resp.Identity.Set(ctx, data.identity())...)
+		return
+	} else if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read custom domain, got error: %s", err))
+		return
+	}
+
+	resp.Diagnostics.Append(data.set(ctx, &domain)...)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, data.identity())...)
+}
+
+func (r *CustomDomainResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	// All attributes known to the platform require replacement,
+	// so only the Terraform-side settings can change here, and the
+	// computed attributes unknown in the plan are kept from the state.
+	var data, state CustomDomainResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	data.CertificateStatus = state.CertificateStatus
+	data.ValidationRecords = state.ValidationRecords
+	data.TargetRecords = state.TargetRecords
+
+	if data.WaitForCertificate.ValueBool() && state.CertificateStatus.ValueString() != CertificateIssued {
+		updateTimeout, diags := data.Timeouts.Update(ctx, defaultCustomDomainCreateTimeout)
+		resp.Diagnostics.Append(diags...)
+		resp.Diagnostics.Append(checkApp(r.client)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		var domain CustomDomain
+		if err := r.waitForCertificate(ctx, data.Env.ValueString(), data.Hostname.ValueString(), &domain, updateTimeout); err != nil {
+			// Keep the prior state, so that the next apply waits again.
+			resp.Diagnostics.AddError("Certificate Error", fmt.Sprintf("The certificate of custom domain %s was not issued: %s", data.Hostname.ValueString(), err))
+			return
+		}
+		resp.Diagnostics.Append(data.set(ctx, &domain)...)
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, data.identity())...)
+}
+
+func (r *CustomDomainResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data CustomDomainResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	resp.Diagnostics.Append(checkApp(r.client)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	err := r.client.Call(ctx, "DELETE", r.path(data.Env.ValueString(), data.Hostname.ValueString()), nil, nil)
+	if err != nil && !IsNotFound(err) {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to delete custom domain, got error: %s", err))
+	}
+}
+
+func (r *CustomDomainResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	var identity CustomDomainIdentityModel
+	if req.ID != "" {
+		env, hostname, ok := strings.Cut(req.ID, "/")
+		if !ok || env == "" || hostname == "" {
+			resp.Diagnostics.AddError("Invalid Import ID", fmt.Sprintf("Expected an id in the form of {env}/{hostname}, got %q", req.ID))
+			return
+		}
+		identity.Env, identity.Hostname = types.StringValue(env), types.StringValue(hostname)
+	} else {
+		resp.Diagnostics.Append(req.Identity.Get(ctx, &identity)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("env"), identity.Env)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("hostname"), identity.Hostname)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("wait_for_certificate"), false)...)
+}
+
+// path returns the platform API path for the custom domains of envName,
+// or for a single domain if a hostname is given.
+func (r *CustomDomainResource) path(envName string, hostname ...string) string {
+	p := escapef("/apps/%s/envs/%s/domains", r.client.AppSlug(), envName)
+	if len(hostname) > 0 {
+		p += escapef("/%s", hostname[0])
+	}
+	return p
+}
+
+// waitForCertificate polls the custom domain hostname of envName into
+// domain until its certificate has been issued, or timeout has passed.
+func (r *CustomDomainResource) waitForCertificate(ctx context.Context, envName, hostname string, domain *CustomDomain, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	return waitFor(ctx, func(ctx context.Context) (bool, error) {
+		err := r.client.Call(ctx, "GET", r.path(envName, hostname), nil, domain)
+		if err != nil {
+			return false, err
+		} else if domain.CertificateStatus == CertificateFailed {
+			return false, errors.New("certificate issuance failed")
+		}
+		return domain.CertificateStatus == CertificateIssued, nil
+	})
+}
+
+func (m *CustomDomainResourceModel) set(ctx context.Context, domain *CustomDomain) (diags diag.Diagnostics) {
+	m.ID = types.StringValue(m.Env.ValueString() + "/" + domain.Hostname)
+	m.Hostname = types.StringValue(domain.Hostname)
+	// Keep a configured path prefix as is, rather than as normalized by
+	// the platform.
+	if m.PathPrefix.IsNull() && domain.PathPrefix != "" {
+		m.PathPrefix = types.StringValue(domain.PathPrefix)
+	}
+	m.CertificateStatus = types.StringValue(domain.CertificateStatus)
+
+	var d diag.Diagnostics
+	m.ValidationRecords, d = types.ListValueFrom(ctx, dnsRecordType, nonNil(domain.ValidationRecords))
+	diags.Append(d...)
+	m.TargetRecords, d = types.ListValueFrom(ctx, dnsRecordType, nonNil(domain.TargetRecords))
+	diags.Append(d...)
+	return diags
+}
+
+func (m *CustomDomainResourceModel) identity() *CustomDomainIdentityModel {
+	return &CustomDomainIdentityModel{Env: m.Env, Hostname: m.Hostname}
+}
+
+// nonNil returns an empty slice instead of nil, so that lists are
+// stored as empty rather than null.
+func nonNil[T any](s []T) []T {
+	if s == nil {
+		return []T{}
+	}
+	return s
+}
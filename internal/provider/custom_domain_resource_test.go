@@ -0,0 +1,177 @@
+package provider
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+)
+
+func testCustomDomainDestroyed(s *terraform.State) error {
+	testPlatform.mu.Lock()
+	defer testPlatform.mu.Unlock()
+	for id := range testPlatform.domains {
+		return fmt.Errorf("custom domain %s still exists", id)
+	}
+	return nil
+}
+
+func TestCustomDomainResource(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testV6ProviderFactories,
+		CheckDestroy:             testCustomDomainDestroyed,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testCustomDomainResourceConfig, "api.example.com", true),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("encore_custom_domain.domain", "id", "fargate/api.example.com"),
+					resource.TestCheckResourceAttr("encore_custom_domain.domain", "env", "fargate"),
+					resource.TestCheckResourceAttr("encore_custom_domain.domain", "path_prefix", "/v1"),
+					resource.TestCheckResourceAttr("encore_custom_domain.domain", "certificate_status", "issued"),
+					resource.TestCheckResourceAttr("encore_custom_domain.domain", "validation_records.#", "1"),
+					resource.TestCheckResourceAttr("encore_custom_domain.domain", "validation_records.0.name", "_acme-challenge.api.example.com"),
+					resource.TestCheckResourceAttr("encore_custom_domain.domain", "validation_records.0.type", "CNAME"),
+					resource.TestCheckResourceAttr("encore_custom_domain.domain", "validation_records.0.value", "api.example.com.validation.encr.app"),
+					resource.TestCheckResourceAttr("encore_custom_domain.domain", "target_records.#", "1"),
+					resource.TestCheckResourceAttr("encore_custom_domain.domain", "target_records.0.name", "api.example.com"),
+					resource.TestCheckResourceAttr("encore_custom_domain.domain", "target_records.0.type", "CNAME"),
+					resource.TestCheckResourceAttr("encore_custom_domain.domain", "target_records.0.value", "fargate.gateway.encr.app"),
+				),
+			},
+			{
+				ResourceName:            "encore_custom_domain.domain",
+				ImportState:             true,
+				ImportStateId:           "fargate/api.example.com",
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"wait_for_certificate", "certificate_status"},
+			},
+			{
+				Config: fmt.Sprintf(testCustomDomainResourceConfig, "www.example.com", false),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("encore_custom_domain.domain", "id", "fargate/www.example.com"),
+					resource.TestCheckResourceAttr("encore_custom_domain.domain", "certificate_status", "pending"),
+				),
+			},
+			{
+				Config: fmt.Sprintf(testCustomDomainResourceConfig, "www.example.com", true),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("encore_custom_domain.domain", plancheck.ResourceActionUpdate),
+					},
+				},
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("encore_custom_domain.domain", "wait_for_certificate", "true"),
+					resource.TestCheckResourceAttr("encore_custom_domain.domain", "certificate_status", "issued"),
+					resource.TestCheckResourceAttr("encore_custom_domain.domain", "validation_records.#", "1"),
+					resource.TestCheckResourceAttr("encore_custom_domain.domain", "target_records.0.value", "fargate.gateway.encr.app"),
+				),
+			},
+		},
+	})
+}
+
+func TestCustomDomainResourceCertificateFailed(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testV6ProviderFactories,
+		CheckDestroy:             testCustomDomainDestroyed,
+		Steps: []resource.TestStep{
+			{
+				Config:      fmt.Sprintf(testCustomDomainResourceConfig, "invalid.example.com", true),
+				ExpectError: regexp.MustCompile("certificate issuance failed"),
+			},
+		},
+	})
+}
+
+func TestCustomDomainResourceWaitOnUpdate(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testV6ProviderFactories,
+		CheckDestroy:             testCustomDomainDestroyed,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testCustomDomainResourceConfig, "invalid.example.com", false),
+				Check:  resource.TestCheckResourceAttr("encore_custom_domain.domain", "certificate_status", "pending"),
+			},
+			{
+				// Enabling wait_for_certificate waits for the certificate.
+				Config:      fmt.Sprintf(testCustomDomainResourceConfig, "invalid.example.com", true),
+				ExpectError: regexp.MustCompile("certificate issuance failed"),
+			},
+			{
+				// The failed wait is retried by the next apply.
+				Config: fmt.Sprintf(testCustomDomainResourceConfig, "invalid.example.com", true),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("encore_custom_domain.domain", plancheck.ResourceActionUpdate),
+					},
+				},
+				ExpectError: regexp.MustCompile("certificate issuance failed"),
+			},
+		},
+	})
+}
+
+func TestCustomDomainResourcePathPrefix(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testV6ProviderFactories,
+		CheckDestroy:             testCustomDomainDestroyed,
+		Steps: []resource.TestStep{
+			{
+				// The platform normalizes the path prefix to "/v1".
+				Config: `
+provider "encore" {
+	auth_key = "test"
+	env = "fargate"
+}
+
+resource "encore_custom_domain" "domain" {
+	hostname    = "api.example.com"
+	path_prefix = "v1"
+}
+`,
+				Check: resource.TestCheckResourceAttr("encore_custom_domain.domain", "path_prefix", "v1"),
+			},
+		},
+	})
+}
+
+func TestCustomDomainResourceMissingApp(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testV6ProviderFactories,
+		CheckDestroy:             testCustomDomainDestroyed,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+provider "encore" {
+	auth_key = "user-key"
+	env = "fargate"
+}
+
+resource "encore_custom_domain" "domain" {
+	hostname = "api.example.com"
+}
+`,
+				ExpectError: regexp.MustCompile("credentials are not scoped to an app"),
+			},
+		},
+	})
+}
+
+const testCustomDomainResourceConfig = `
+provider "encore" {
+	auth_key = "test"
+	env = "fargate"
+}
+
+resource "encore_custom_domain" "domain" {
+	hostname             = "%s"
+	path_prefix          = "/v1"
+	wait_for_certificate = %t
+	timeouts = {
+		create = "1m"
+	}
+}
+`
@@ -2,11 +2,14 @@ package provider
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"maps"
 	"reflect"
 	"slices"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
@@ -16,6 +19,12 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hasura/go-graphql-client"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 
 	"encr.dev/pkg/idents"
 )
@@ -84,6 +93,35 @@ func getAttribute(fieldTyp reflect.Type, desc string) (rtn schema.Attribute, dia
 	return nil, diags
 }
 
+// getNullValue returns the null value of the attribute of type typ.
+func getNullValue(typ reflect.Type) (attr.Value, diag.Diagnostics) {
+	att, diags := getAttribute(typ, "")
+	if diags.HasError() {
+		return nil, diags
+	}
+	ctx := context.Background()
+	null, err := att.GetType().ValueFromTerraform(ctx, tftypes.NewValue(att.GetType().TerraformType(ctx), nil))
+	if err != nil {
+		diags.AddError("Value Conversion Error", err.Error())
+	}
+	return null, diags
+}
+
+// isTypename reports whether field holds the GraphQL type name of its struct.
+func isTypename(field reflect.StructField) bool {
+	return field.Tag.Get("graphql") == "__typename"
+}
+
+// getTypename returns the GraphQL type name queried by the struct val, if any.
+func getTypename(val reflect.Value) string {
+	for i := 0; i < val.NumField(); i++ {
+		if isTypename(val.Type().Field(i)) {
+			return val.Field(i).String()
+		}
+	}
+	return ""
+}
+
 func containsFragment(field reflect.StructField, fragmentFilter ...string) bool {
 	fragment := strings.TrimPrefix(field.Tag.Get("graphql"), "... on ")
 	if len(fragmentFilter) == 0 || slices.Contains(fragmentFilter, fragment) {
@@ -111,7 +149,7 @@ func getAttributes(typ reflect.Type, fragmentFilter ...string) (rtn map[string]s
 	rtn = make(map[string]schema.Attribute)
 	for i := 0; i < typ.NumField(); i++ {
 		field := typ.Field(i)
-		if !containsFragment(field, fragmentFilter...) {
+		if !containsFragment(field, fragmentFilter...) || isTypename(field) {
 			continue
 		}
 		name := getTFName(field)
@@ -189,14 +227,26 @@ func getValues(val reflect.Value, fragmentFilter ...string) (rtn map[string]attr
 		diags.AddError("Unsupported Type", fmt.Sprintf("unsupported type %s", val.Kind()))
 		return nil, diags
 	}
+	// Of a union that queries its type name, only the member it is an
+	// instance of is set, and the others are null.
+	var typename string
+	if len(fragmentFilter) == 0 {
+		typename = getTypename(val)
+	}
 	for i := 0; i < val.NumField(); i++ {
 		field := val.Type().Field(i)
-		if !containsFragment(field, fragmentFilter...) {
+		if !containsFragment(field, fragmentFilter...) || isTypename(field) {
 			continue
 		}
 		name := getTFName(field)
 		val := val.Field(i)
-		attr, diags := getValue(val)
+		var attr attr.Value
+		var diags diag.Diagnostics
+		if typename != "" && !containsFragment(field, typename) {
+			attr, diags = getNullValue(field.Type)
+		} else {
+			attr, diags = getValue(val)
+		}
 		if diags.HasError() {
 			return nil, diags
 		} else if attr == nil {
@@ -231,6 +281,12 @@ type TerraformDescription interface {
 	GetDocs() (attrDesc map[string]string)
 }
 
+// NeedsDataSource is a data source reading needs of the type it returns,
+// which are fetched along with those of the other data sources.
+type NeedsDataSource interface {
+	GetTypeRef() TypeRef
+}
+
 type Need struct {
 	ID         string
 	TypeRef    TypeRef
@@ -240,28 +296,48 @@ type Need struct {
 
 func NewNeedsData(client PlatformClient, envName string, ds []func() datasource.DataSource) *NeedsData {
 	if envName == "" {
-		envName = "@primary"
+		envName = primaryEnv
 	}
 	n := &NeedsData{
 		client:     client,
-		needs:      map[string]map[TypeRef]map[string]*Need{},
+		needs:      map[needsKey]map[TypeRef]map[string]*Need{},
 		defaultEnv: envName,
 	}
 	for _, d := range ds {
-		ds := d()
-		if ds, ok := ds.(*EncoreDataSource); ok {
-			n.types = append(n.types, ds.typeRef)
+		if ds, ok := d().(NeedsDataSource); ok {
+			n.types = append(n.types, ds.GetTypeRef())
 		}
-
 	}
 	return n
 }
 
+// needsKey identifies the environment of an app whose needs are cached.
+type needsKey struct {
+	app, env string
+}
+
+// primaryEnv is the name of the primary environment of an app.
+const primaryEnv = "@primary"
+
 type NeedsData struct {
-	needs      map[string]map[TypeRef]map[string]*Need
 	client     PlatformClient
 	defaultEnv string
 	types      []TypeRef
+
+	// mu guards the needs cache and the warning about dropped fields,
+	// since Terraform reads data sources concurrently. It is held while
+	// fetching, so that the needs of an environment are fetched once.
+	mu     sync.Mutex
+	needs  map[needsKey]map[TypeRef]map[string]*Need
+	warned bool
+
+	// schemaOnce guards the introspection of the platform schema, which
+	// limits the needs query to the fields the platform supports, and the
+	// fields left out of the needs query for that schema.
+	schemaOnce sync.Once
+	schema     platformSchema
+	rootType   string
+	dropped    []droppedField
 }
 
 func createSchema(desc string, fragments ...string) schema.Schema {
@@ -277,6 +353,10 @@ func createSchema(desc string, fragments ...string) schema.Schema {
 		Optional:            true,
 		MarkdownDescription: "The environment of the Encore resource. Defaults to the provider environment",
 	}
+	attrs["app"] = schema.StringAttribute{
+		Optional:            true,
+		MarkdownDescription: "The slug of the Encore app of the resource. Defaults to the provider app",
+	}
 	return schema.Schema{
 		MarkdownDescription: desc,
 		Attributes:          attrs,
@@ -284,25 +364,30 @@ func createSchema(desc string, fragments ...string) schema.Schema {
 }
 
 func (s *NeedsData) SetValue(ctx context.Context, typRef TypeRef, reqCfg tfsdk.Config, state *tfsdk.State) diag.Diagnostics {
-	var encoreName, envName types.String
+	var encoreName, envName, appSlug types.String
 	var diags diag.Diagnostics
 
 	diags.Append(reqCfg.GetAttribute(ctx, path.Root("name"), &encoreName)...)
 	diags.Append(reqCfg.GetAttribute(ctx, path.Root("env"), &envName)...)
+	diags.Append(reqCfg.GetAttribute(ctx, path.Root("app"), &appSlug)...)
 	if diags.HasError() {
 		return diags
 	}
 	if envName.ValueString() == "" {
 		envName = types.StringValue(s.defaultEnv)
 	}
+	if appSlug.ValueString() == "" {
+		appSlug = types.StringValue(s.client.AppSlug())
+	}
 	diags.Append(state.SetAttribute(ctx, path.Root("name"), encoreName)...)
 	diags.Append(state.SetAttribute(ctx, path.Root("env"), envName)...)
+	diags.Append(state.SetAttribute(ctx, path.Root("app"), appSlug)...)
 
 	if diags.HasError() {
 		return diags
 	}
 
-	n, diags := s.Get(ctx, typRef, envName.ValueString(), encoreName.ValueString())
+	n, diags := s.Get(ctx, typRef, appSlug.ValueString(), envName.ValueString(), encoreName.ValueString())
 	if diags.HasError() {
 		return diags
 	}
@@ -317,14 +402,42 @@ func (s *NeedsData) SetValue(ctx context.Context, typRef TypeRef, reqCfg tfsdk.C
 	for key, val := range values {
 		diags.Append(state.SetAttribute(ctx, path.Root(key), val)...)
 	}
+	diags.Append(s.setDropped(ctx, n.Satisfier.Type, state)...)
 	return diags
 }
 
-func (n *NeedsData) Get(ctx context.Context, typRef TypeRef, envName, encoreName string) (*Need, diag.Diagnostics) {
-	if envName == "" {
-		envName = n.defaultEnv
+// setDropped sets the attributes of a satisfier of type satisfier that
+// were left out of the needs query to null, rather than their zero value.
+// Attributes within lists are left as is.
+func (s *NeedsData) setDropped(ctx context.Context, satisfier string, state *tfsdk.State) diag.Diagnostics {
+	var diags diag.Diagnostics
+	for _, d := range s.dropped {
+		if d.Satisfier != satisfier || d.Attribute == "" {
+			continue
+		}
+		steps := strings.Split(d.Attribute, ".")
+		p := path.Root(steps[0])
+		for _, step := range steps[1:] {
+			p = p.AtName(step)
+		}
+		typ, typDiags := state.Schema.TypeAtPath(ctx, p)
+		if typDiags.HasError() {
+			continue
+		}
+		null, err := typ.ValueFromTerraform(ctx, tftypes.NewValue(typ.TerraformType(ctx), nil))
+		if err != nil {
+			diags.AddAttributeError(p, "Value Conversion Error", err.Error())
+			continue
+		}
+		diags.Append(state.SetAttribute(ctx, p, null)...)
 	}
-	envNeeds, diags := n.envNeeds(ctx, envName)
+	return diags
+}
+
+// Get returns the need of type typRef named encoreName in the given app and
+// environment, defaulting to the provider app and environment if empty.
+func (n *NeedsData) Get(ctx context.Context, typRef TypeRef, appSlug, envName, encoreName string) (*Need, diag.Diagnostics) {
+	envNeeds, diags := n.lookup(ctx, appSlug, envName)
 	if diags.HasError() {
 		return nil, diags
 	}
@@ -334,28 +447,138 @@ func (n *NeedsData) Get(ctx context.Context, typRef TypeRef, envName, encoreName
 	return envNeeds[typRef][encoreName], nil
 }
 
+// List returns the needs of type typRef in the given app and environment,
+// sorted by name, defaulting to the provider app and environment if empty.
+func (n *NeedsData) List(ctx context.Context, typRef TypeRef, appSlug, envName string) ([]*Need, diag.Diagnostics) {
+	envNeeds, diags := n.lookup(ctx, appSlug, envName)
+	if diags.HasError() {
+		return nil, diags
+	}
+	var needs []*Need
+	for _, name := range slices.Sorted(maps.Keys(envNeeds[typRef])) {
+		needs = append(needs, envNeeds[typRef][name])
+	}
+	return needs, diags
+}
+
+// lookup returns the needs of the given app and environment, defaulting to
+// the provider app and environment if empty.
+func (n *NeedsData) lookup(ctx context.Context, appSlug, envName string) (map[TypeRef]map[string]*Need, diag.Diagnostics) {
+	if appSlug == "" {
+		appSlug = n.client.AppSlug()
+	}
+	if envName == "" {
+		envName = n.defaultEnv
+	}
+	// Snapshots need not be organized by app.
+	if _, snapshot := n.client.(*snapshotClient); appSlug == "" && !snapshot {
+		var diags diag.Diagnostics
+		diags.AddAttributeError(path.Root("app"), "Missing App", missingAppDetail+" or data source.")
+		return nil, diags
+	}
+	return n.envNeeds(ctx, needsKey{app: appSlug, env: envName})
+}
+
+// missingAppDetail explains that the app to operate on is not known.
+const missingAppDetail = "The provider credentials are not scoped to an app. Set `app` on the provider"
+
+// checkApp reports an error if client does not operate on an app, as is
+// the case when authenticating as a user without setting `app`, rather
+// than building platform API paths without one.
+func checkApp(client PlatformClient) (diags diag.Diagnostics) {
+	// The app of credentials that are not known yet is not known either.
+	if _, unknown := client.(*unknownConfigClient); unknown {
+		return nil
+	}
+	if client.AppSlug() == "" {
+		diags.AddError("Missing App", missingAppDetail+".")
+	}
+	return diags
+}
+
 type TypeRef string
 
-func (n *NeedsData) envNeeds(ctx context.Context, envName string) (map[TypeRef]map[string]*Need, diag.Diagnostics) {
-	if envNeeds, ok := n.needs[envName]; ok {
+// needsOperation is the GraphQL operation name of the needsQuery.
+const needsOperation = "EnvNeeds"
+
+// needsQuery is the GraphQL query for the needs of an environment.
+type needsQuery struct {
+	App struct {
+		Env struct {
+			Needs []*Need `graphql:"needs(sel:{typeRefs:$types})"`
+		} `graphql:"env(name: $envName)"`
+	} `graphql:"app(slug: $appSlug)"`
+}
+
+// queryVars returns the variables of the needsQuery for key.
+func (n *NeedsData) queryVars(key needsKey) map[string]interface{} {
+	return map[string]interface{}{
+		"appSlug": key.app,
+		"envName": key.env,
+		"types":   n.types,
+	}
+}
+
+// Snapshot returns the raw GraphQL response for the needs of an
+// environment, which can be used as the snapshot_file of the provider.
+func (n *NeedsData) Snapshot(ctx context.Context, appSlug, envName string) ([]byte, error) {
+	vars := n.queryVars(needsKey{app: appSlug, env: envName})
+	query, _, err := n.needsQueryString(ctx, vars)
+	if err != nil {
+		return nil, err
+	}
+	data, err := n.client.GQL().ExecRaw(ctx, query, vars, graphql.OperationName(needsOperation))
+	if err != nil {
+		return nil, err
+	}
+	return json.MarshalIndent(struct {
+		Data json.RawMessage `json:"data"`
+	}{data}, "", "  ")
+}
+
+func (n *NeedsData) envNeeds(ctx context.Context, key needsKey) (map[TypeRef]map[string]*Need, diag.Diagnostics) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if envNeeds, ok := n.needs[key]; ok {
 		return envNeeds, nil
 	}
-	var q struct {
-		App struct {
-			Env struct {
-				Needs []*Need `graphql:"needs(sel:{typeRefs:$types})"`
-			} `graphql:"env(name: $envName)"`
-		} `graphql:"app(slug: $appSlug)"`
+	ctx, span := tracer.Start(ctx, "encore.needs", trace.WithAttributes(
+		attribute.String("encore.app", key.app),
+		attribute.String("encore.env", key.env),
+	))
+	defer span.End()
+	ctx = platformLogContext(ctx)
+	fields := map[string]interface{}{
+		"app":           key.app,
+		"env":           key.env,
+		"gql_operation": needsOperation,
 	}
-	err := n.client.GQL().Query(ctx, &q, map[string]interface{}{
-		"appSlug": n.client.AppSlug(),
-		"envName": envName,
-		"types":   n.types,
-	})
+
+	var diags diag.Diagnostics
+	vars := n.queryVars(key)
+	query, dropped, err := n.needsQueryString(ctx, vars)
 	if err != nil {
-		var diags diag.Diagnostics
+		diags.AddError("Client Error", fmt.Sprintf("Unable to build the needs query, got error: %s", err))
+		return nil, diags
+	}
+	if len(dropped) > 0 && !n.warned {
+		n.warned = true
+		diags.AddWarning("Unsupported Attributes", droppedWarning(dropped))
+	}
+
+	var q needsQuery
+	start := time.Now()
+	err = n.client.GQL().Exec(ctx, query, &q, vars, graphql.OperationName(needsOperation))
+	fields["duration_ms"] = time.Since(start).Milliseconds()
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		fields["error"] = err.Error()
+		tflog.SubsystemDebug(ctx, logSubsystem, "unable to fetch needs", fields)
 		if strings.Contains(err.Error(), "env not found") {
 			diags.AddAttributeError(path.Root("env"), "Env not found", "The specified environment does not exist")
+		} else if strings.Contains(err.Error(), "app not found") {
+			diags.AddAttributeError(path.Root("app"), "App not found", "The specified app does not exist or is not accessible with the provider credentials")
 		} else {
 			diags.AddError("Client Error", fmt.Sprintf("Unable to fetch Encore resources, got error: %s", err))
 		}
@@ -368,6 +591,44 @@ func (n *NeedsData) envNeeds(ctx context.Context, envName string) (map[TypeRef]m
 		}
 		envTypes[need.TypeRef][need.EncoreName] = need
 	}
-	n.needs[envName] = envTypes
-	return envTypes, nil
+	fields["needs"] = len(q.App.Env.Needs)
+	tflog.SubsystemDebug(ctx, logSubsystem, "fetched needs", fields)
+	n.needs[key] = envTypes
+	return envTypes, diags
+}
+
+// readUnknown handles the read of a data source whose lookup inputs, the
+// string attributes named by inputs, are not known yet. It defers the read
+// if Terraform supports it, and otherwise leaves all computed attributes
+// unknown until the inputs are known. It reports whether any input was unknown.
+func readUnknown(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse, inputs ...string) bool {
+	unknown := false
+	for _, name := range inputs {
+		var v types.String
+		resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root(name), &v)...)
+		unknown = unknown || v.IsUnknown()
+	}
+	if !unknown || resp.Diagnostics.HasError() {
+		return unknown
+	}
+
+	if req.ClientCapabilities.DeferralAllowed {
+		resp.Deferred = &datasource.Deferred{Reason: datasource.DeferredReasonDataSourceConfigUnknown}
+		return true
+	}
+	attrs := req.Config.Schema.GetAttributes()
+	raw, err := tftypes.Transform(req.Config.Raw, func(p *tftypes.AttributePath, v tftypes.Value) (tftypes.Value, error) {
+		if steps := p.Steps(); len(steps) == 1 {
+			if name, ok := steps[0].(tftypes.AttributeName); ok && attrs[string(name)].IsComputed() {
+				return tftypes.NewValue(v.Type(), tftypes.UnknownValue), nil
+			}
+		}
+		return v, nil
+	})
+	if err != nil {
+		resp.Diagnostics.AddError("Internal Error", fmt.Sprintf("Unable to mark attributes unknown, got error: %s", err))
+		return true
+	}
+	resp.State.Raw = raw
+	return true
 }
@@ -13,7 +13,7 @@ func TestData(t *testing.T) {
 	c := qt.New(t)
 	c.Skip("skipping test in CI")
 	ctx := context.Background()
-	client := NewPlatformClient("test")
+	client := NewPlatformClient("test", ClientOptions{})
 	err := client.Auth(ctx, os.Getenv("ENCORE_AUTH_KEY"))
 	c.Assert(err, qt.IsNil)
 	nd := NewNeedsData(client, "staging", []func() datasource.DataSource{
@@ -24,6 +24,6 @@ func TestData(t *testing.T) {
 		NewService,
 		NewGateway,
 	})
-	_, diags := nd.Get(ctx, "need.Topic", "", "test")
+	_, diags := nd.Get(ctx, "need.Topic", "", "", "test")
 	c.Assert(diags, qt.HasLen, 0)
 }
@@ -0,0 +1,231 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// renewBefore is how long before a lease expires that Terraform is asked to renew it.
+const renewBefore = time.Minute
+
+var (
+	_ ephemeral.EphemeralResourceWithConfigure = &DatabaseCredentialsEphemeralResource{}
+	_ ephemeral.EphemeralResourceWithRenew     = &DatabaseCredentialsEphemeralResource{}
+	_ ephemeral.EphemeralResourceWithClose     = &DatabaseCredentialsEphemeralResource{}
+)
+
+func NewDatabaseCredentials() ephemeral.EphemeralResource {
+	return &DatabaseCredentialsEphemeralResource{}
+}
+
+// DatabaseCredentials are short-lived database credentials as returned by the Encore Platform.
+type DatabaseCredentials struct {
+	LeaseID   string    `json:"lease_id"`
+	Host      string    `json:"host"`
+	Port      int64     `json:"port"`
+	Database  string    `json:"database"`
+	User      string    `json:"user"`
+	Password  string    `json:"password"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// databaseCredentialsLease is the private state of an open credentials lease.
+type databaseCredentialsLease struct {
+	Env      string `json:"env"`
+	Name     string `json:"name"`
+	LeaseID  string `json:"lease_id"`
+	ReadOnly bool   `json:"read_only"`
+}
+
+const databaseCredentialsLeaseKey = "lease"
+
+type DatabaseCredentialsEphemeralResource struct {
+	client     PlatformClient
+	defaultEnv string
+}
+
+// DatabaseCredentialsEphemeralResourceModel describes the ephemeral resource data model.
+type DatabaseCredentialsEphemeralResourceModel struct {
+	Env       types.String `tfsdk:"env"`
+	Name      types.String `tfsdk:"name"`
+	ReadOnly  types.Bool   `tfsdk:"read_only"`
+	Host      types.String `tfsdk:"host"`
+	Port      types.Int64  `tfsdk:"port"`
+	Database  types.String `tfsdk:"database"`
+	User      types.String `tfsdk:"user"`
+	Password  types.String `tfsdk:"password"`
+	ExpiresAt types.String `tfsdk:"expires_at"`
+}
+
+func (r *DatabaseCredentialsEphemeralResource) Metadata(ctx context.Context, req ephemeral.MetadataRequest, resp *ephemeral.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_database_credentials"
+}
+
+func (r *DatabaseCredentialsEphemeralResource) Schema(ctx context.Context, req ephemeral.SchemaRequest, resp *ephemeral.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Short-lived credentials for an Encore provisioned database. " +
+			"The credentials are leased for the duration of the Terraform operation, renewed as needed and revoked afterwards.",
+		Attributes: map[string]schema.Attribute{
+			"env": schema.StringAttribute{
+				MarkdownDescription: "The environment of the database. Defaults to the provider environment",
+				Optional:            true,
+				Computed:            true,
+			},
+			"name": schema.StringAttribute{
+				MarkdownDescription: "The Encore name of the database",
+				Required:            true,
+			},
+			"read_only": schema.BoolAttribute{
+				MarkdownDescription: "Request credentials that can only read from the database. Defaults to `false`",
+				Optional:            true,
+			},
+			"host": schema.StringAttribute{
+				MarkdownDescription: "The hostname of the database server",
+				Computed:            true,
+			},
+			"port": schema.Int64Attribute{
+				MarkdownDescription: "The port of the database server",
+				Computed:            true,
+			},
+			"database": schema.StringAttribute{
+				MarkdownDescription: "The name of the database on the database server. May be different than the Encore name",
+				Computed:            true,
+			},
+			"user": schema.StringAttribute{
+				MarkdownDescription: "The user to connect as",
+				Computed:            true,
+			},
+			"password": schema.StringAttribute{
+				MarkdownDescription: "The password of the user",
+				Computed:            true,
+				Sensitive:           true,
+			},
+			"expires_at": schema.StringAttribute{
+				MarkdownDescription: "The time the credentials expire unless renewed, in RFC 3339 format",
+				Computed:            true,
+			},
+		},
+	}
+}
+
+func (r *DatabaseCredentialsEphemeralResource) Configure(ctx context.Context, req ephemeral.ConfigureRequest, resp *ephemeral.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	needs, ok := req.ProviderData.(*NeedsData)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Ephemeral Resource Configure Type",
+			fmt.Sprintf("Expected *NeedsData, received %T", req.ProviderData),
+		)
+		return
+	}
+
+	r.client = needs.client
+	r.defaultEnv = needs.defaultEnv
+}
+
+func (r *DatabaseCredentialsEphemeralResource) Open(ctx context.Context, req ephemeral.OpenRequest, resp *ephemeral.OpenResponse) {
+	var data DatabaseCredentialsEphemeralResourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	resp.Diagnostics.Append(checkApp(r.client)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if data.Env.ValueString() == "" {
+		data.Env = types.StringValue(r.defaultEnv)
+	}
+	lease := databaseCredentialsLease{
+		Env:      data.Env.ValueString(),
+		Name:     data.Name.ValueString(),
+		ReadOnly: data.ReadOnly.ValueBool(),
+	}
+
+	var creds DatabaseCredentials
+	err := r.client.Call(ctx, "POST", r.path(lease), struct {
+		ReadOnly bool `json:"read_only"`
+	}{lease.ReadOnly}, &creds)
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to create credentials for database %s, got error: %s", lease.Name, err))
+		return
+	}
+	lease.LeaseID = creds.LeaseID
+	tflog.Debug(ctx, "leased database credentials", map[string]interface{}{
+		"database": lease.Name,
+		"lease_id": lease.LeaseID,
+	})
+
+	data.Host = types.StringValue(creds.Host)
+	data.Port = types.Int64Value(creds.Port)
+	data.Database = types.StringValue(creds.Database)
+	data.User = types.StringValue(creds.User)
+	data.Password = types.StringValue(creds.Password)
+	data.ExpiresAt = timeValue(&creds.ExpiresAt)
+	resp.Diagnostics.Append(resp.Result.Set(ctx, &data)...)
+
+	privateData, err := json.Marshal(lease)
+	if err != nil {
+		resp.Diagnostics.AddError("Internal Error", fmt.Sprintf("Unable to encode credentials lease, got error: %s", err))
+		return
+	}
+	resp.Diagnostics.Append(resp.Private.SetKey(ctx, databaseCredentialsLeaseKey, privateData)...)
+	resp.RenewAt = creds.ExpiresAt.Add(-renewBefore)
+}
+
+func (r *DatabaseCredentialsEphemeralResource) Renew(ctx context.Context, req ephemeral.RenewRequest, resp *ephemeral.RenewResponse) {
+	lease, diags := r.lease(ctx, req.Private)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var creds DatabaseCredentials
+	err := r.client.Call(ctx, "POST", r.path(lease)+escapef("/%s:renew", lease.LeaseID), nil, &creds)
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to renew credentials for database %s, got error: %s", lease.Name, err))
+		return
+	}
+	resp.RenewAt = creds.ExpiresAt.Add(-renewBefore)
+}
+
+func (r *DatabaseCredentialsEphemeralResource) Close(ctx context.Context, req ephemeral.CloseRequest, resp *ephemeral.CloseResponse) {
+	lease, diags := r.lease(ctx, req.Private)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	err := r.client.Call(ctx, "DELETE", r.path(lease)+escapef("/%s", lease.LeaseID), nil, nil)
+	if err != nil && !IsNotFound(err) {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to revoke credentials for database %s, got error: %s", lease.Name, err))
+	}
+}
+
+// lease reads the credentials lease from the private state.
+func (r *DatabaseCredentialsEphemeralResource) lease(ctx context.Context, private interface {
+	GetKey(context.Context, string) ([]byte, diag.Diagnostics)
+}) (lease databaseCredentialsLease, diags diag.Diagnostics) {
+	privateData, diags := private.GetKey(ctx, databaseCredentialsLeaseKey)
+	if diags.HasError() {
+		return lease, diags
+	}
+	if err := json.Unmarshal(privateData, &lease); err != nil {
+		diags.AddError("Internal Error", fmt.Sprintf("Unable to decode credentials lease, got error: %s", err))
+	}
+	return lease, diags
+}
+
+// path returns the platform API path for the credentials of the leased database.
+func (r *DatabaseCredentialsEphemeralResource) path(lease databaseCredentialsLease) string {
+	return escapef("/apps/%s/envs/%s/databases/%s/credentials", r.client.AppSlug(), lease.Env, lease.Name)
+}
@@ -0,0 +1,95 @@
+package provider
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-testing/echoprovider"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"github.com/hashicorp/terraform-plugin-testing/tfversion"
+)
+
+// testEchoProviderFactories adds the echo provider, which copies ephemeral
+// values into state so that they can be checked.
+var testEchoProviderFactories = map[string]func() (tfprotov6.ProviderServer, error){
+	"encore": testV6ProviderFactories["encore"],
+	"echo":   echoprovider.NewProviderServer(),
+}
+
+// testLeasesRevoked checks that all leases of database in env have been
+// renewed and revoked.
+func testLeasesRevoked(env, database string) resource.TestCheckFunc {
+	return func(*terraform.State) error {
+		testPlatform.mu.Lock()
+		defer testPlatform.mu.Unlock()
+		n := 0
+		for id, l := range testPlatform.leases {
+			if !regexp.MustCompile("^" + regexp.QuoteMeta(env+"/"+database+"/")).MatchString(id) {
+				continue
+			}
+			n++
+			if l.Renewals == 0 {
+				return fmt.Errorf("lease %s was never renewed", id)
+			} else if !l.Revoked {
+				return fmt.Errorf("lease %s was not revoked", id)
+			}
+		}
+		if n == 0 {
+			return fmt.Errorf("no leases for database %s in %s", database, env)
+		}
+		return nil
+	}
+}
+
+func TestDatabaseCredentialsEphemeralResource(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testEchoProviderFactories,
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_10_0),
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testDatabaseCredentialsConfig, false, "creds"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("echo.creds", "data.env", "db-credentials"),
+					resource.TestCheckResourceAttr("echo.creds", "data.host", "userdb.db-credentials.db.encr.app"),
+					resource.TestCheckResourceAttr("echo.creds", "data.port", "5432"),
+					resource.TestCheckResourceAttr("echo.creds", "data.database", "userdb"),
+					resource.TestCheckResourceAttr("echo.creds", "data.user", "userdb_writer"),
+					resource.TestCheckResourceAttr("echo.creds", "data.password", "hunter2"),
+					resource.TestCheckResourceAttrSet("echo.creds", "data.expires_at"),
+					testLeasesRevoked("db-credentials", "userdb"),
+				),
+			},
+			{
+				Config: fmt.Sprintf(testDatabaseCredentialsConfig, true, "reader_creds"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("echo.reader_creds", "data.user", "userdb_reader"),
+					testLeasesRevoked("db-credentials", "userdb"),
+				),
+			},
+		},
+	})
+}
+
+const testDatabaseCredentialsConfig = `
+provider "encore" {
+	auth_key = "test"
+	env = "db-credentials"
+}
+
+ephemeral "encore_database_credentials" "db" {
+	name      = "userdb"
+	read_only = %t
+}
+
+provider "echo" {
+	data = ephemeral.encore_database_credentials.db
+}
+
+// The echo resource only copies the data on creation.
+resource "echo" "%s" {}
+`
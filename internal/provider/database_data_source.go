@@ -28,28 +28,141 @@ type SQLDatabase struct {
 }
 
 type SQLServer struct {
-	AwsRds      AWSSQLServer `graphql:"... on AWSSQLServer"`
-	GcpCloudSQL GCPSQLServer `graphql:"... on GCPSQLServer"`
+	Type                  string           `graphql:"__typename"`
+	AwsRds                AWSSQLServer     `graphql:"... on AWSSQLServer"`
+	GcpCloudSQL           GCPSQLServer     `graphql:"... on GCPSQLServer"`
+	AwsAurora             AWSAuroraCluster `graphql:"... on AWSAuroraCluster"`
+	AwsRdsReplicaSet      AWSRDSReplicaSet `graphql:"... on AWSRDSReplicaSet"`
+	GcpCloudSQLReplicaSet GCPSQLReplicaSet `graphql:"... on GCPSQLReplicaSet"`
 }
 
 func (a *SQLServer) GetDocs() map[string]string {
 	return map[string]string{
-		"aws_rds":       "Set if the database server instance is an AWS RDS instance",
-		"gcp_cloud_sql": "Set if the database server instance is a GCP Cloud SQL instance",
+		"aws_rds":                   "Set if the database server instance is an AWS RDS instance",
+		"gcp_cloud_sql":             "Set if the database server instance is a GCP Cloud SQL instance",
+		"aws_aurora":                "Set if the database server is an AWS Aurora cluster",
+		"aws_rds_replica_set":       "Set if the database server is an AWS RDS instance with read replicas",
+		"gcp_cloud_sql_replica_set": "Set if the database server is a GCP Cloud SQL instance with read replicas",
+	}
+}
+
+type AWSAuroraCluster struct {
+	Arn            string
+	Endpoint       string
+	ReaderEndpoint string
+	Port           int
+	Engine         string
+	EngineVersion  string
+	MajorVersion   int
+	Writer         AWSAuroraInstance
+	Readers        []AWSAuroraInstance
+	VPC            AWSVPC
+	SubnetGroup    AWSSubnetGroup
+	SecurityGroup  AWSSecurityGroup
+	ParameterGroup AWSParameterGroup
+}
+
+func (a *AWSAuroraCluster) GetDocs() map[string]string {
+	return map[string]string{
+		"arn":             "The [ARN](https://docs.aws.amazon.com/IAM/latest/UserGuide/reference-arns.html) for the Aurora cluster",
+		"endpoint":        "The hostname of the [cluster endpoint](https://docs.aws.amazon.com/AmazonRDS/latest/AuroraUserGuide/Aurora.Overview.Endpoints.html), which connects to the writer instance",
+		"reader_endpoint": "The hostname of the reader endpoint, which balances connections across the reader instances",
+		"port":            "The port the Aurora cluster listens on",
+		"engine":          "The database engine of the Aurora cluster, e.g. `aurora-postgresql`",
+		"engine_version":  "The version of the database engine, e.g. `15.4`",
+		"major_version":   "The major version of the database engine, e.g. `15`",
+		"writer":          "The writer instance of the Aurora cluster",
+		"readers":         "The reader instances of the Aurora cluster",
+		"vpc":             "The [VPC](https://docs.aws.amazon.com/vpc/latest/userguide/what-is-amazon-vpc.html) that the Aurora cluster is connected to",
+		"subnet_group":    "The [subnet group](https://docs.aws.amazon.com/AmazonRDS/latest/UserGuide/USER_VPC.WorkingWithRDSInstanceinaVPC.html) that the Aurora cluster is connected to",
+		"security_group":  "The [security group](https://docs.aws.amazon.com/vpc/latest/userguide/VPC_SecurityGroups.html) that the Aurora cluster is connected to",
+		"parameter_group": "The [cluster parameter group](https://docs.aws.amazon.com/AmazonRDS/latest/AuroraUserGuide/USER_WorkingWithParamGroups.html) that the Aurora cluster uses",
+	}
+}
+
+type AWSAuroraInstance struct {
+	Arn           string
+	Endpoint      string
+	InstanceClass string
+	Az            string
+}
+
+func (a *AWSAuroraInstance) GetDocs() map[string]string {
+	return map[string]string{
+		"arn":            "The [ARN](https://docs.aws.amazon.com/IAM/latest/UserGuide/reference-arns.html) for the instance",
+		"endpoint":       "The hostname of the instance endpoint",
+		"instance_class": "The [instance class](https://docs.aws.amazon.com/AmazonRDS/latest/AuroraUserGuide/Concepts.DBInstanceClass.html) of the instance, e.g. `db.r6g.large`",
+		"az":             "The [availability zone](https://docs.aws.amazon.com/AWSEC2/latest/UserGuide/using-regions-availability-zones.html) of the instance",
+	}
+}
+
+type AWSRDSReplicaSet struct {
+	Primary  AWSSQLServer
+	Replicas []AWSSQLServer
+}
+
+func (a *AWSRDSReplicaSet) GetDocs() map[string]string {
+	return map[string]string{
+		"primary":  "The primary RDS instance, which accepts writes",
+		"replicas": "The [read replicas](https://docs.aws.amazon.com/AmazonRDS/latest/UserGuide/USER_ReadRepl.html) of the primary instance",
+	}
+}
+
+type GCPSQLReplicaSet struct {
+	Primary  GCPSQLServer
+	Replicas []GCPSQLServer
+}
+
+func (a *GCPSQLReplicaSet) GetDocs() map[string]string {
+	return map[string]string{
+		"primary":  "The primary Cloud SQL instance, which accepts writes",
+		"replicas": "The [read replicas](https://cloud.google.com/sql/docs/postgres/replication) of the primary instance",
 	}
 }
 
 type GCPSQLServer struct {
-	SelfLink string `tf:"id"`
-	Network  GCPNetwork
-	SslCert  GCPSSLCert
+	SelfLink       string `tf:"id"`
+	Endpoint       string
+	ReaderEndpoint string
+	Port           int
+	Engine         string
+	EngineVersion  string
+	MajorVersion   int
+	InstanceClass  string
+	Storage        SQLStorage
+	Network        GCPNetwork
+	SslCert        GCPSSLCert
 }
 
 func (a *GCPSQLServer) GetDocs() map[string]string {
 	return map[string]string{
-		"id":       "The [id](https://cloud.google.com/apis/design/resource_names#relative_resource_name) in the form of `projects/{project}/instances/{instance}`",
-		"network":  "The [network](https://cloud.google.com/vpc/docs/vpc) that the database instance is connected to",
-		"ssl_cert": "The [SSL certificate](https://cloud.google.com/sql/docs/mysql/configure-ssl-instance) for the database instance",
+		"id":              "The [id](https://cloud.google.com/apis/design/resource_names#relative_resource_name) in the form of `projects/{project}/instances/{instance}`",
+		"endpoint":        "The private IP address of the database instance",
+		"reader_endpoint": "The private IP address of the read replica of the database instance. Empty if the instance has no read replicas",
+		"port":            "The port the database instance listens on",
+		"engine":          "The database engine of the database instance, e.g. `postgres`",
+		"engine_version":  "The version of the database engine, e.g. `15.4`",
+		"major_version":   "The major version of the database engine, e.g. `15`",
+		"instance_class":  "The [machine type](https://cloud.google.com/sql/docs/postgres/instance-settings) (tier) of the database instance, e.g. `db-custom-2-7680`",
+		"storage":         "The storage settings of the database instance",
+		"network":         "The [network](https://cloud.google.com/vpc/docs/vpc) that the database instance is connected to",
+		"ssl_cert":        "The [SSL certificate](https://cloud.google.com/sql/docs/mysql/configure-ssl-instance) for the database instance",
+	}
+}
+
+type SQLStorage struct {
+	Type      string
+	SizeGb    int
+	MaxSizeGb int
+	Encrypted bool
+}
+
+func (a *SQLStorage) GetDocs() map[string]string {
+	return map[string]string{
+		"type":        "The storage type, e.g. `gp3` on AWS or `PD_SSD` on GCP",
+		"size_gb":     "The allocated storage in GiB",
+		"max_size_gb": "The limit in GiB up to which storage grows automatically. 0 if storage autoscaling is disabled",
+		"encrypted":   "Whether the storage is encrypted with a customer managed key",
 	}
 }
 
@@ -86,6 +199,14 @@ func (a *GCPSubnet) GetDocs() map[string]string {
 
 type AWSSQLServer struct {
 	Arn            string
+	Endpoint       string
+	ReaderEndpoint string
+	Port           int
+	Engine         string
+	EngineVersion  string
+	MajorVersion   int
+	InstanceClass  string
+	Storage        SQLStorage
 	VPC            AWSVPC
 	SubnetGroup    AWSSubnetGroup
 	SecurityGroup  AWSSecurityGroup
@@ -95,6 +216,14 @@ type AWSSQLServer struct {
 func (a *AWSSQLServer) GetDocs() map[string]string {
 	return map[string]string{
 		"arn":             "The [ARN](https://docs.aws.amazon.com/IAM/latest/UserGuide/reference-arns.html) for the database server instance",
+		"endpoint":        "The hostname of the database instance",
+		"reader_endpoint": "The hostname of the read replica of the database instance. Empty if the instance has no read replicas",
+		"port":            "The port the database instance listens on",
+		"engine":          "The database engine of the database instance, e.g. `postgres`",
+		"engine_version":  "The version of the database engine, e.g. `15.4`",
+		"major_version":   "The major version of the database engine, e.g. `15`",
+		"instance_class":  "The [instance class](https://docs.aws.amazon.com/AmazonRDS/latest/UserGuide/Concepts.DBInstanceClass.html) of the database instance, e.g. `db.t4g.medium`",
+		"storage":         "The storage settings of the database instance",
 		"vpc":             "The [VPC](https://docs.aws.amazon.com/vpc/latest/userguide/what-is-amazon-vpc.html) that the database instance is connected to",
 		"subnet_group":    "The [subnet group](https://docs.aws.amazon.com/AmazonRDS/latest/UserGuide/USER_VPC.WorkingWithRDSInstanceinaVPC.html) that the database instance is connected to",
 		"security_group":  "The [security group](https://docs.aws.amazon.com/vpc/latest/userguide/VPC_SecurityGroups.html) that the database instance is connected to",
@@ -10,12 +10,25 @@ func testAWSRDS() resource.TestCheckFunc {
 	return resource.ComposeAggregateTestCheckFunc(
 		resource.TestCheckResourceAttr("data.encore_sql_database.database", "database_name", "todo"),
 		resource.TestCheckResourceAttr("data.encore_sql_database.database", "aws_rds.arn", "arn:aws:rds:region:account:db"),
+		resource.TestCheckNoResourceAttr("data.encore_sql_database.database", "aws_aurora.arn"),
+		resource.TestCheckNoResourceAttr("data.encore_sql_database.database", "gcp_cloud_sql.id"),
 		resource.TestCheckResourceAttr("data.encore_sql_database.database", "aws_rds.vpc.id", "vpc"),
 		resource.TestCheckResourceAttr("data.encore_sql_database.database", "aws_rds.subnet_group.arn", "arn:aws:rds:region:account:subgrp:app-env"),
 		resource.TestCheckResourceAttr("data.encore_sql_database.database", "aws_rds.security_group.id", "sg"),
 		resource.TestCheckResourceAttr("data.encore_sql_database.database", "aws_rds.parameter_group.arn", "arn:aws:rds:region:account:pg:rds-instance"),
 		resource.TestCheckResourceAttr("data.encore_sql_database.database", "aws_rds.subnet_group.arn", "arn:aws:rds:region:account:subgrp:app-env"),
 		testAWSSubnets("data.encore_sql_database.database", "aws_rds.subnet_group"),
+		resource.TestCheckResourceAttr("data.encore_sql_database.database", "aws_rds.endpoint", "app-env.abc123xyz.us-east-1.rds.amazonaws.com"),
+		resource.TestCheckResourceAttr("data.encore_sql_database.database", "aws_rds.reader_endpoint", ""),
+		resource.TestCheckResourceAttr("data.encore_sql_database.database", "aws_rds.port", "5432"),
+		resource.TestCheckResourceAttr("data.encore_sql_database.database", "aws_rds.engine", "postgres"),
+		resource.TestCheckResourceAttr("data.encore_sql_database.database", "aws_rds.engine_version", "15.4"),
+		resource.TestCheckResourceAttr("data.encore_sql_database.database", "aws_rds.major_version", "15"),
+		resource.TestCheckResourceAttr("data.encore_sql_database.database", "aws_rds.instance_class", "db.t4g.medium"),
+		resource.TestCheckResourceAttr("data.encore_sql_database.database", "aws_rds.storage.type", "gp3"),
+		resource.TestCheckResourceAttr("data.encore_sql_database.database", "aws_rds.storage.size_gb", "20"),
+		resource.TestCheckResourceAttr("data.encore_sql_database.database", "aws_rds.storage.max_size_gb", "100"),
+		resource.TestCheckResourceAttr("data.encore_sql_database.database", "aws_rds.storage.encrypted", "true"),
 	)
 }
 
@@ -23,8 +36,66 @@ func testGCPCloudSQL() resource.TestCheckFunc {
 	return resource.ComposeAggregateTestCheckFunc(
 		resource.TestCheckResourceAttr("data.encore_sql_database.database", "database_name", "todo"),
 		resource.TestCheckResourceAttr("data.encore_sql_database.database", "gcp_cloud_sql.id", "projects/app-env/regions/northamerica-northeast1/instances/app-env"),
+		resource.TestCheckNoResourceAttr("data.encore_sql_database.database", "gcp_cloud_sql_replica_set.primary.id"),
+		resource.TestCheckNoResourceAttr("data.encore_sql_database.database", "aws_rds.arn"),
 		resource.TestCheckResourceAttr("data.encore_sql_database.database", "gcp_cloud_sql.network.id", "projects/app-env/global/networks/default"),
 		resource.TestCheckResourceAttr("data.encore_sql_database.database", "gcp_cloud_sql.ssl_cert.fingerprint", "fingerprint"),
+		resource.TestCheckResourceAttr("data.encore_sql_database.database", "gcp_cloud_sql.endpoint", "10.20.0.3"),
+		resource.TestCheckResourceAttr("data.encore_sql_database.database", "gcp_cloud_sql.port", "5432"),
+		resource.TestCheckResourceAttr("data.encore_sql_database.database", "gcp_cloud_sql.engine", "postgres"),
+		resource.TestCheckResourceAttr("data.encore_sql_database.database", "gcp_cloud_sql.major_version", "15"),
+		resource.TestCheckResourceAttr("data.encore_sql_database.database", "gcp_cloud_sql.instance_class", "db-custom-2-7680"),
+		resource.TestCheckResourceAttr("data.encore_sql_database.database", "gcp_cloud_sql.storage.type", "PD_SSD"),
+		resource.TestCheckResourceAttr("data.encore_sql_database.database", "gcp_cloud_sql.storage.size_gb", "10"),
+		resource.TestCheckResourceAttr("data.encore_sql_database.database", "gcp_cloud_sql.storage.max_size_gb", "0"),
+	)
+}
+
+func testAWSAurora() resource.TestCheckFunc {
+	return resource.ComposeAggregateTestCheckFunc(
+		resource.TestCheckResourceAttr("data.encore_sql_database.database", "database_name", "analytics"),
+		resource.TestCheckNoResourceAttr("data.encore_sql_database.database", "aws_rds.arn"),
+		resource.TestCheckNoResourceAttr("data.encore_sql_database.database", "aws_rds_replica_set.primary.arn"),
+		resource.TestCheckResourceAttr("data.encore_sql_database.database", "aws_aurora.arn", "arn:aws:rds:region:account:cluster:app-env-analytics"),
+		resource.TestCheckResourceAttr("data.encore_sql_database.database", "aws_aurora.endpoint", "app-env-analytics.cluster-abc123xyz.us-east-1.rds.amazonaws.com"),
+		resource.TestCheckResourceAttr("data.encore_sql_database.database", "aws_aurora.reader_endpoint", "app-env-analytics.cluster-ro-abc123xyz.us-east-1.rds.amazonaws.com"),
+		resource.TestCheckResourceAttr("data.encore_sql_database.database", "aws_aurora.port", "5432"),
+		resource.TestCheckResourceAttr("data.encore_sql_database.database", "aws_aurora.engine", "aurora-postgresql"),
+		resource.TestCheckResourceAttr("data.encore_sql_database.database", "aws_aurora.major_version", "15"),
+		resource.TestCheckResourceAttr("data.encore_sql_database.database", "aws_aurora.writer.arn", "arn:aws:rds:region:account:db:app-env-analytics-1"),
+		resource.TestCheckResourceAttr("data.encore_sql_database.database", "aws_aurora.writer.az", "us-east-1a"),
+		resource.TestCheckResourceAttr("data.encore_sql_database.database", "aws_aurora.readers.#", "1"),
+		resource.TestCheckResourceAttr("data.encore_sql_database.database", "aws_aurora.readers.0.endpoint", "app-env-analytics-2.abc123xyz.us-east-1.rds.amazonaws.com"),
+		resource.TestCheckResourceAttr("data.encore_sql_database.database", "aws_aurora.readers.0.instance_class", "db.r6g.large"),
+		resource.TestCheckResourceAttr("data.encore_sql_database.database", "aws_aurora.parameter_group.arn", "arn:aws:rds:region:account:cluster-pg:app-env-analytics"),
+		testAWSSubnets("data.encore_sql_database.database", "aws_aurora.subnet_group"),
+	)
+}
+
+func testAWSRDSReplicaSet() resource.TestCheckFunc {
+	return resource.ComposeAggregateTestCheckFunc(
+		resource.TestCheckResourceAttr("data.encore_sql_database.database", "database_name", "analytics"),
+		resource.TestCheckNoResourceAttr("data.encore_sql_database.database", "aws_rds.arn"),
+		resource.TestCheckNoResourceAttr("data.encore_sql_database.database", "aws_aurora.arn"),
+		resource.TestCheckResourceAttr("data.encore_sql_database.database", "aws_rds_replica_set.primary.arn", "arn:aws:rds:region:account:db"),
+		resource.TestCheckResourceAttr("data.encore_sql_database.database", "aws_rds_replica_set.primary.reader_endpoint", "app-env-replica-1.abc123xyz.us-east-1.rds.amazonaws.com"),
+		resource.TestCheckResourceAttr("data.encore_sql_database.database", "aws_rds_replica_set.replicas.#", "1"),
+		resource.TestCheckResourceAttr("data.encore_sql_database.database", "aws_rds_replica_set.replicas.0.arn", "arn:aws:rds:region:account:db:appenv-replica-1"),
+		resource.TestCheckResourceAttr("data.encore_sql_database.database", "aws_rds_replica_set.replicas.0.endpoint", "app-env-replica-1.abc123xyz.us-east-1.rds.amazonaws.com"),
+		testAWSSubnets("data.encore_sql_database.database", "aws_rds_replica_set.replicas.0.subnet_group"),
+	)
+}
+
+func testGCPCloudSQLReplicaSet() resource.TestCheckFunc {
+	return resource.ComposeAggregateTestCheckFunc(
+		resource.TestCheckResourceAttr("data.encore_sql_database.database", "database_name", "analytics"),
+		resource.TestCheckNoResourceAttr("data.encore_sql_database.database", "gcp_cloud_sql.id"),
+		resource.TestCheckResourceAttr("data.encore_sql_database.database", "gcp_cloud_sql_replica_set.primary.id", "projects/app-env/regions/northamerica-northeast1/instances/app-env"),
+		resource.TestCheckResourceAttr("data.encore_sql_database.database", "gcp_cloud_sql_replica_set.primary.reader_endpoint", "10.20.0.4"),
+		resource.TestCheckResourceAttr("data.encore_sql_database.database", "gcp_cloud_sql_replica_set.replicas.#", "1"),
+		resource.TestCheckResourceAttr("data.encore_sql_database.database", "gcp_cloud_sql_replica_set.replicas.0.id", "projects/app-env/regions/northamerica-northeast1/instances/app-env-replica-1"),
+		resource.TestCheckResourceAttr("data.encore_sql_database.database", "gcp_cloud_sql_replica_set.replicas.0.endpoint", "10.20.0.4"),
+		resource.TestCheckResourceAttr("data.encore_sql_database.database", "gcp_cloud_sql_replica_set.replicas.0.network.id", "projects/app-env/global/networks/default"),
 	)
 }
 
@@ -56,6 +127,29 @@ func TestDatabaseDataSource(t *testing.T) {
 	})
 }
 
+func TestDatabaseDataSourceReplicas(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testV6ProviderFactories,
+		Steps: []resource.TestStep{
+			testStepForEnv(
+				"eks",
+				testDatabaseDataSourceReplicasConfig,
+				testAWSAurora(),
+			),
+			testStepForEnv(
+				"fargate",
+				testDatabaseDataSourceReplicasConfig,
+				testAWSRDSReplicaSet(),
+			),
+			testStepForEnv(
+				"gke",
+				testDatabaseDataSourceReplicasConfig,
+				testGCPCloudSQLReplicaSet(),
+			),
+		},
+	})
+}
+
 const testDatabaseDataSourceConfig = `
 provider "encore" {
 	auth_key = "test"
@@ -66,3 +160,14 @@ data "encore_sql_database" "database" {
     name = "todo"
 }
 `
+
+const testDatabaseDataSourceReplicasConfig = `
+provider "encore" {
+	auth_key = "test"
+	env = "%s"
+}
+
+data "encore_sql_database" "database" {
+    name = "analytics"
+}
+`
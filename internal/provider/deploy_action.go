@@ -0,0 +1,90 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/action"
+	"github.com/hashicorp/terraform-plugin-framework/action/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var (
+	_ action.ActionWithConfigure      = &DeployAction{}
+	_ action.ActionWithValidateConfig = &DeployAction{}
+)
+
+func NewDeployAction() action.Action {
+	return &DeployAction{}
+}
+
+type DeployAction struct {
+	platformAction
+}
+
+// DeployActionModel describes the action data model.
+type DeployActionModel struct {
+	Env     types.String `tfsdk:"env"`
+	Commit  types.String `tfsdk:"commit"`
+	Branch  types.String `tfsdk:"branch"`
+	Timeout types.String `tfsdk:"timeout"`
+}
+
+func (a *DeployAction) Metadata(ctx context.Context, req action.MetadataRequest, resp *action.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_deploy"
+}
+
+func (a *DeployAction) Schema(ctx context.Context, req action.SchemaRequest, resp *action.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Deploys a commit to an Encore environment and waits for the deployment to complete.",
+		Attributes: map[string]schema.Attribute{
+			"env": schema.StringAttribute{
+				MarkdownDescription: "The environment to deploy to. Defaults to the provider environment",
+				Optional:            true,
+			},
+			"commit": schema.StringAttribute{
+				MarkdownDescription: "The git commit SHA to deploy. Exactly one of `commit` and `branch` must be set",
+				Optional:            true,
+			},
+			"branch": schema.StringAttribute{
+				MarkdownDescription: "The git branch to deploy the latest commit of. Exactly one of `commit` and `branch` must be set",
+				Optional:            true,
+			},
+			"timeout": timeoutAttribute("deployment"),
+		},
+	}
+}
+
+func (a *DeployAction) ValidateConfig(ctx context.Context, req action.ValidateConfigRequest, resp *action.ValidateConfigResponse) {
+	var data DeployActionModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.Append(validateCommitOrBranch(data.Commit, data.Branch)...)
+	_, diags := actionTimeout(data.Timeout)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (a *DeployAction) Invoke(ctx context.Context, req action.InvokeRequest, resp *action.InvokeResponse) {
+	var data DeployActionModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	resp.Diagnostics.Append(checkApp(a.client)...)
+	timeout, diags := actionTimeout(data.Timeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	envName := a.env(data.Env)
+
+	var deploy Deployment
+	err := a.client.Call(ctx, "POST", deploymentsPath(a.client.AppSlug(), envName), struct {
+		Commit string `json:"commit,omitempty"`
+		Branch string `json:"branch,omitempty"`
+	}{data.Commit.ValueString(), data.Branch.ValueString()}, &deploy)
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to create deployment, got error: %s", err))
+		return
+	}
+	resp.Diagnostics.Append(a.waitForDeployment(ctx, resp, envName, &deploy, timeout)...)
+}
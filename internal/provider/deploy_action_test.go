@@ -0,0 +1,104 @@
+package provider
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"github.com/hashicorp/terraform-plugin-testing/tfversion"
+)
+
+// testDeployments checks the commits and statuses of the deployments
+// of env in the test platform, oldest first.
+func testDeployments(env string, want ...string) resource.TestCheckFunc {
+	return func(*terraform.State) error {
+		testPlatform.mu.Lock()
+		defer testPlatform.mu.Unlock()
+		var got []string
+		for _, d := range testPlatform.deploys[env] {
+			got = append(got, d.Commit+":"+d.Status)
+		}
+		if fmt.Sprint(got) != fmt.Sprint(want) {
+			return fmt.Errorf("got deployments %v, want %v", got, want)
+		}
+		return nil
+	}
+}
+
+func TestDeployAction(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testV6ProviderFactories,
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_14_0),
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testDeployActionConfig, "deploy-action", "commit = \"abc123\"\ntimeout = \"1m\""),
+				Check:  testDeployments("deploy-action", "abc123:success"),
+			},
+		},
+	})
+}
+
+func TestDeployActionFailure(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testV6ProviderFactories,
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_14_0),
+		},
+		Steps: []resource.TestStep{
+			{
+				Config:      fmt.Sprintf(testDeployActionConfig, "deploy-action-failure", "commit = \"abc123\"\nbranch = \"main\""),
+				ExpectError: regexp.MustCompile("Exactly one of `commit` and `branch` must be set"),
+			},
+			{
+				Config:      fmt.Sprintf(testDeployActionConfig, "deploy-action-failure", `commit = "bad123"`),
+				ExpectError: regexp.MustCompile(`with\s+status\s+"failure"`),
+			},
+		},
+	})
+}
+
+func TestDeployActionTimeout(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testV6ProviderFactories,
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_14_0),
+		},
+		Steps: []resource.TestStep{
+			{
+				Config:      fmt.Sprintf(testDeployActionConfig, "deploy-action-timeout", "commit = \"abc123\"\ntimeout = \"soon\""),
+				ExpectError: regexp.MustCompile("Invalid Timeout"),
+			},
+			{
+				Config:      fmt.Sprintf(testDeployActionConfig, "deploy-action-timeout", "commit = \"abc123\"\ntimeout = \"1ms\""),
+				ExpectError: regexp.MustCompile(`context\s+deadline\s+exceeded`),
+			},
+		},
+	})
+}
+
+const testDeployActionConfig = `
+provider "encore" {
+	auth_key = "test"
+	env = "%s"
+}
+
+action "encore_deploy" "deploy" {
+	config {
+		%s
+	}
+}
+
+resource "terraform_data" "trigger" {
+	input = "v1"
+	lifecycle {
+		action_trigger {
+			events  = [after_create]
+			actions = [action.encore_deploy.deploy]
+		}
+	}
+}
+`
@@ -0,0 +1,135 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var _ datasource.DataSourceWithConfigure = &DeploymentDataSource{}
+
+func NewDeploymentDataSource() datasource.DataSource {
+	return &DeploymentDataSource{}
+}
+
+type DeploymentDataSource struct {
+	client     PlatformClient
+	defaultEnv string
+}
+
+// DeploymentDataSourceModel describes the data source data model.
+type DeploymentDataSourceModel struct {
+	ID         types.String `tfsdk:"id"`
+	App        types.String `tfsdk:"app"`
+	Env        types.String `tfsdk:"env"`
+	Commit     types.String `tfsdk:"commit"`
+	Branch     types.String `tfsdk:"branch"`
+	Status     types.String `tfsdk:"status"`
+	CreatedAt  types.String `tfsdk:"created_at"`
+	FinishedAt types.String `tfsdk:"finished_at"`
+}
+
+func (d *DeploymentDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_deployment"
+}
+
+func (d *DeploymentDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "The latest successful deployment of an Encore environment",
+		Attributes: map[string]schema.Attribute{
+			"env": schema.StringAttribute{
+				MarkdownDescription: "The environment of the deployment. Defaults to the provider environment",
+				Optional:            true,
+			},
+			"app": schema.StringAttribute{
+				MarkdownDescription: "The slug of the app of the deployment. Defaults to the provider app",
+				Optional:            true,
+			},
+			"id": schema.StringAttribute{
+				MarkdownDescription: "The id of the deployment",
+				Computed:            true,
+			},
+			"commit": schema.StringAttribute{
+				MarkdownDescription: "The git commit SHA that was deployed",
+				Computed:            true,
+			},
+			"branch": schema.StringAttribute{
+				MarkdownDescription: "The git branch that was deployed, if the deployment was triggered from a branch",
+				Computed:            true,
+			},
+			"status": schema.StringAttribute{
+				MarkdownDescription: "The status of the deployment",
+				Computed:            true,
+			},
+			"created_at": schema.StringAttribute{
+				MarkdownDescription: "The time the deployment was created, in RFC 3339 format",
+				Computed:            true,
+			},
+			"finished_at": schema.StringAttribute{
+				MarkdownDescription: "The time the deployment finished, in RFC 3339 format",
+				Computed:            true,
+			},
+		},
+	}
+}
+
+func (d *DeploymentDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	needs, ok := req.ProviderData.(*NeedsData)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *NeedsData, received %T", req.ProviderData),
+		)
+		return
+	}
+
+	d.client = needs.client
+	d.defaultEnv = needs.defaultEnv
+}
+
+func (d *DeploymentDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	if readUnknown(ctx, req, resp, "env", "app") {
+		return
+	}
+	var data DeploymentDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if data.Env.ValueString() == "" {
+		data.Env = types.StringValue(d.defaultEnv)
+	}
+	if data.App.ValueString() == "" {
+		data.App = types.StringValue(d.client.AppSlug())
+	}
+
+	var deploys Page[*Deployment]
+	err := d.client.Call(ctx, "GET", deploymentsPath(data.App.ValueString(), data.Env.ValueString())+"?status=success&limit=1", nil, &deploys)
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to list deployments, got error: %s", err))
+		return
+	} else if len(deploys.Items) == 0 {
+		resp.Diagnostics.AddError("No Deployment Found", fmt.Sprintf("Environment %s has no successful deployments", data.Env.ValueString()))
+		return
+	}
+
+	data.set(deploys.Items[0])
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (m *DeploymentDataSourceModel) set(deploy *Deployment) {
+	m.ID = types.StringValue(deploy.ID)
+	m.Commit = types.StringValue(deploy.Commit)
+	m.Branch = types.StringValue(deploy.Branch)
+	m.Status = types.StringValue(deploy.Status)
+	m.CreatedAt = timeValue(&deploy.CreatedAt)
+	m.FinishedAt = timeValue(deploy.FinishedAt)
+}
@@ -0,0 +1,57 @@
+package provider
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestDeploymentDataSource(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+provider "encore" {
+	auth_key = "test"
+}
+
+data "encore_deployment" "latest" {
+	env = "fargate"
+}
+`,
+				ExpectError: regexp.MustCompile("Environment fargate has no successful deployments"),
+			},
+			{
+				Config: testDeploymentDataSourceConfig + `
+data "encore_deployment" "latest" {
+	env        = "gke"
+	depends_on = [encore_deployment.deploy]
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.encore_deployment.latest", "id", "deploy_gke_1"),
+					resource.TestCheckResourceAttr("data.encore_deployment.latest", "env", "gke"),
+					resource.TestCheckResourceAttr("data.encore_deployment.latest", "app", "test"),
+					resource.TestCheckResourceAttr("data.encore_deployment.latest", "commit", "head-of-main"),
+					resource.TestCheckResourceAttr("data.encore_deployment.latest", "branch", "main"),
+					resource.TestCheckResourceAttr("data.encore_deployment.latest", "status", "success"),
+					resource.TestCheckResourceAttr("data.encore_deployment.latest", "created_at", "2024-01-02T03:04:05Z"),
+					resource.TestCheckResourceAttr("data.encore_deployment.latest", "finished_at", "2024-01-02T03:05:05Z"),
+				),
+			},
+		},
+	})
+}
+
+const testDeploymentDataSourceConfig = `
+provider "encore" {
+	auth_key = "test"
+}
+
+resource "encore_deployment" "deploy" {
+	env    = "gke"
+	branch = "main"
+}
+`
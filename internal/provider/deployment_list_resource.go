@@ -0,0 +1,78 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+
+	"github.com/hashicorp/terraform-plugin-framework/list"
+	"github.com/hashicorp/terraform-plugin-framework/list/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var _ list.ListResourceWithConfigure = &DeploymentListResource{}
+
+func NewDeploymentList() list.ListResource {
+	return &DeploymentListResource{}
+}
+
+// DeploymentListResource lists the deployments of an environment, newest first.
+// It shares Metadata and Configure with DeploymentResource.
+type DeploymentListResource struct {
+	DeploymentResource
+}
+
+// DeploymentListResourceModel describes the list resource config data model.
+type DeploymentListResourceModel struct {
+	Env    types.String `tfsdk:"env"`
+	Status types.String `tfsdk:"status"`
+}
+
+func (r *DeploymentListResource) ListResourceConfigSchema(ctx context.Context, req list.ListResourceSchemaRequest, resp *list.ListResourceSchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Lists the deployments of an Encore environment, newest first.",
+		Attributes: map[string]schema.Attribute{
+			"env": schema.StringAttribute{
+				MarkdownDescription: "The environment to list the deployments of. Defaults to the provider environment",
+				Optional:            true,
+			},
+			"status": schema.StringAttribute{
+				MarkdownDescription: "Only list deployments with this status, e.g. `success`",
+				Optional:            true,
+			},
+		},
+	}
+}
+
+func (r *DeploymentListResource) List(ctx context.Context, req list.ListRequest, stream *list.ListResultsStream) {
+	var config DeploymentListResourceModel
+	diags := req.Config.Get(ctx, &config)
+	diags.Append(checkApp(r.client)...)
+	if diags.HasError() {
+		stream.Results = list.ListResultsStreamDiagnostics(diags)
+		return
+	}
+	envName := config.Env.ValueString()
+	if envName == "" {
+		envName = r.defaultEnv
+	}
+	query := url.Values{}
+	if status := config.Status.ValueString(); status != "" {
+		query.Set("status", status)
+	}
+
+	deploys := paginate[*Deployment](ctx, r.client, deploymentsPath(r.client.AppSlug(), envName), query, listPageSize(req))
+	stream.Results = listResults(ctx, req, deploys, "deployments", func(deploy *Deployment, result *list.ListResult) {
+		data := DeploymentResourceModel{
+			Env:      types.StringValue(envName),
+			Branch:   types.StringNull(),
+			Timeouts: nullTimeouts("create"),
+		}
+		data.set(deploy)
+		result.DisplayName = fmt.Sprintf("%s (commit %s, %s)", deploy.ID, deploy.Commit, deploy.Status)
+		result.Diagnostics.Append(result.Identity.Set(ctx, data.identity())...)
+		if req.IncludeResource {
+			result.Diagnostics.Append(result.Resource.Set(ctx, &data)...)
+		}
+	})
+}
@@ -0,0 +1,85 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/querycheck"
+	"github.com/hashicorp/terraform-plugin-testing/querycheck/queryfilter"
+	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
+	"github.com/hashicorp/terraform-plugin-testing/tfversion"
+)
+
+func TestDeploymentListResource(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testV6ProviderFactories,
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_14_0),
+		},
+		Steps: []resource.TestStep{
+			{
+				PreConfig: func() {
+					testPlatform.mu.Lock()
+					defer testPlatform.mu.Unlock()
+					testPlatform.newDeployment("deploy-list", "aaa", "").Status = DeploymentSuccess
+					testPlatform.newDeployment("deploy-list", "bad", "").Status = DeploymentFailure
+				},
+				Config: testDeploymentListResourceConfig,
+			},
+			{
+				// The provider is configured by the previous step's configuration.
+				Query: true,
+				Config: `
+list "encore_deployment" "successful" {
+	provider         = encore
+	include_resource = true
+	config {
+		status = "success"
+	}
+}
+`,
+				QueryResultChecks: []querycheck.QueryResultCheck{
+					querycheck.ExpectLength("encore_deployment.successful", 2),
+					querycheck.ExpectIdentity("encore_deployment.successful", map[string]knownvalue.Check{
+						"env": knownvalue.StringExact("deploy-list"),
+						"id":  knownvalue.StringExact("deploy_deploy-list_1"),
+					}),
+					querycheck.ExpectResourceDisplayName("encore_deployment.successful", queryfilter.ByResourceIdentity(map[string]knownvalue.Check{
+						"env": knownvalue.StringExact("deploy-list"),
+						"id":  knownvalue.StringExact("deploy_deploy-list_3"),
+					}), knownvalue.StringExact("deploy_deploy-list_3 (commit abc123, success)")),
+					querycheck.ExpectResourceKnownValues("encore_deployment.successful", queryfilter.ByResourceIdentity(map[string]knownvalue.Check{
+						"env": knownvalue.StringExact("deploy-list"),
+						"id":  knownvalue.StringExact("deploy_deploy-list_3"),
+					}), []querycheck.KnownValueCheck{
+						{Path: tfjsonpath.New("commit"), KnownValue: knownvalue.StringExact("abc123")},
+						{Path: tfjsonpath.New("finished_at"), KnownValue: knownvalue.StringExact("2024-01-02T03:05:05Z")},
+					}),
+				},
+			},
+			{
+				Query: true,
+				Config: `
+list "encore_deployment" "all" {
+	provider = encore
+}
+`,
+				QueryResultChecks: []querycheck.QueryResultCheck{
+					querycheck.ExpectLength("encore_deployment.all", 3),
+				},
+			},
+		},
+	})
+}
+
+const testDeploymentListResourceConfig = `
+provider "encore" {
+	auth_key = "test"
+	env = "deploy-list"
+}
+
+resource "encore_deployment" "deploy" {
+	commit = "abc123"
+}
+`
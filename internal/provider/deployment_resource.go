@@ -0,0 +1,381 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/identityschema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+const defaultDeploymentCreateTimeout = 30 * time.Minute
+
+var (
+	_ resource.Resource                   = &DeploymentResource{}
+	_ resource.ResourceWithConfigure      = &DeploymentResource{}
+	_ resource.ResourceWithValidateConfig = &DeploymentResource{}
+	_ resource.ResourceWithImportState    = &DeploymentResource{}
+	_ resource.ResourceWithIdentity       = &DeploymentResource{}
+)
+
+func NewDeployment() resource.Resource {
+	return &DeploymentResource{}
+}
+
+// Deployment is a deployment as returned by the Encore Platform.
+type Deployment struct {
+	ID         string     `json:"id"`
+	Commit     string     `json:"commit"`
+	Branch     string     `json:"branch"`
+	Status     string     `json:"status"`
+	Error      string     `json:"error"`
+	CreatedAt  time.Time  `json:"created_at"`
+	FinishedAt *time.Time `json:"finished_at"`
+}
+
+const (
+	DeploymentQueued   = "queued"
+	DeploymentRunning  = "running"
+	DeploymentSuccess  = "success"
+	DeploymentFailure  = "failure"
+	DeploymentCanceled = "canceled"
+)
+
+// Done reports whether the deployment has reached a terminal status.
+func (d *Deployment) Done() bool {
+	return isTerminalStatus(d.Status)
+}
+
+func isTerminalStatus(status string) bool {
+	switch status {
+	case DeploymentSuccess, DeploymentFailure, DeploymentCanceled:
+		return true
+	}
+	return false
+}
+
+func deploymentsPath(appSlug, envName string, id ...string) string {
+	p := escapef("/apps/%s/envs/%s/deploys", appSlug, envName)
+	if len(id) > 0 {
+		p += escapef("/%s", id[0])
+	}
+	return p
+}
+
+type DeploymentResource struct {
+	client     PlatformClient
+	defaultEnv string
+}
+
+// DeploymentResourceModel describes the resource data model.
+type DeploymentResourceModel struct {
+	ID         types.String   `tfsdk:"id"`
+	Env        types.String   `tfsdk:"env"`
+	Commit     types.String   `tfsdk:"commit"`
+	Branch     types.String   `tfsdk:"branch"`
+	Status     types.String   `tfsdk:"status"`
+	CreatedAt  types.String   `tfsdk:"created_at"`
+	FinishedAt types.String   `tfsdk:"finished_at"`
+	Timeouts   timeouts.Value `tfsdk:"timeouts"`
+}
+
+// DeploymentIdentityModel describes the resource identity data model.
+type DeploymentIdentityModel struct {
+	Env types.String `tfsdk:"env"`
+	ID  types.String `tfsdk:"id"`
+}
+
+func (r *DeploymentResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_deployment"
+}
+
+func (r *DeploymentResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Deploys a commit to an Encore environment. " +
+			"Changing the commit or branch triggers a new deployment. " +
+			"Destroying the resource only removes it from the Terraform state; the deployed code keeps running. " +
+			"Importing an existing deployment adopts it without deploying again.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				MarkdownDescription: "The id of the deployment",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"env": schema.StringAttribute{
+				MarkdownDescription: "The environment to deploy to. Defaults to the provider environment",
+				Optional:            true,
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"commit": schema.StringAttribute{
+				MarkdownDescription: "The git commit SHA to deploy, which may be abbreviated. Exactly one of `commit` and `branch` must be set. " +
+					"If `branch` is set, this is the commit the branch pointed to when it was deployed",
+				Optional: true,
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"branch": schema.StringAttribute{
+				MarkdownDescription: "The git branch to deploy the latest commit of. Exactly one of `commit` and `branch` must be set",
+				Optional:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"status": schema.StringAttribute{
+				MarkdownDescription: "The status of the deployment. One of `queued`, `running`, `success`, `failure` or `canceled`",
+				Computed:            true,
+			},
+			"created_at": schema.StringAttribute{
+				MarkdownDescription: "The time the deployment was created, in RFC 3339 format",
+				Computed:            true,
+			},
+			"finished_at": schema.StringAttribute{
+				MarkdownDescription: "The time the deployment finished, in RFC 3339 format",
+				Computed:            true,
+			},
+			"timeouts": timeouts.Attributes(ctx, timeouts.Opts{
+				Create: true,
+			}),
+		},
+	}
+}
+
+func (r *DeploymentResource) IdentitySchema(ctx context.Context, req resource.IdentitySchemaRequest, resp *resource.IdentitySchemaResponse) {
+	resp.IdentitySchema = identityschema.Schema{
+		Attributes: map[string]identityschema.Attribute{
+			"env": identityschema.StringAttribute{
+				Description:       "The environment that was deployed to",
+				RequiredForImport: true,
+			},
+			"id": identityschema.StringAttribute{
+				Description:       "The id of the deployment",
+				RequiredForImport: true,
+			},
+		},
+	}
+}
+
+func (r *DeploymentResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var data DeploymentResourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.Append(validateCommitOrBranch(data.Commit, data.Branch)...)
+}
+
+// validateCommitOrBranch checks that exactly one of commit and branch is set.
+func validateCommitOrBranch(commit, branch types.String) (diags diag.Diagnostics) {
+	if commit.IsUnknown() || branch.IsUnknown() {
+		return nil
+	}
+	if commit.IsNull() == branch.IsNull() {
+		diags.AddAttributeError(path.Root("commit"), "Invalid Attribute Combination", "Exactly one of `commit` and `branch` must be set")
+	}
+	return diags
+}
+
+func (r *DeploymentResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	needs, ok := req.ProviderData.(*NeedsData)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *NeedsData, received %T", req.ProviderData),
+		)
+		return
+	}
+
+	r.client = needs.client
+	r.defaultEnv = needs.defaultEnv
+}
+
+func (r *DeploymentResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data DeploymentResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	resp.Diagnostics.Append(checkApp(r.client)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if data.Env.ValueString() == "" {
+		data.Env = types.StringValue(r.defaultEnv)
+	}
+
+	createTimeout, diags := data.Timeouts.Create(ctx, defaultDeploymentCreateTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var deploy Deployment
+	err := r.client.Call(ctx, "POST", deploymentsPath(r.client.AppSlug(), data.Env.ValueString()), struct {
+		Commit string `json:"commit,omitempty"`
+		Branch string `json:"branch,omitempty"`
+	}{data.Commit.ValueString(), data.Branch.ValueString()}, &deploy)
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to create deployment, got error: %s", err))
+		return
+	}
+
+	waitCtx, cancel := context.WithTimeout(ctx, createTimeout)
+	defer cancel()
+	err = waitForDeployment(waitCtx, r.client, data.Env.ValueString(), &deploy, nil)
+
+	// Keep failed deployments in state so that they are tainted and retried.
+	data.set(&deploy)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, data.identity())...)
+	if err != nil {
+		resp.Diagnostics.AddError("Deployment Error", fmt.Sprintf("Unable to wait for deployment %s to complete, got error: %s", deploy.ID, err))
+	} else if deploy.Status != DeploymentSuccess {
+		resp.Diagnostics.AddError("Deployment Error", deploymentFailure(&deploy))
+	}
+}
+
+func (r *DeploymentResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data DeploymentResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	resp.Diagnostics.Append(checkApp(r.client)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var deploy Deployment
+	err := r.client.Call(ctx, "GET", deploymentsPath(r.client.AppSlug(), data.Env.ValueString(), data.ID.ValueString()), nil, &deploy)
+	if IsNotFound(err) {
+		resp.State.RemoveResource(ctx)
+		return
+	} else if errors.Is(err, errConfigUnknown) {
+		// Keep the prior state until the provider can authenticate during apply.
+		resp.Diagnostics.Append(resp.Identity.Set(ctx, data.identity())...)
+		return
+	} else if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read deployment, got error: %s", err))
+		return
+	}
+
+	data.set(&deploy)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, data.identity())...)
+}
+
+func (r *DeploymentResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	// All attributes known to the platform require replacement,
+	// so only the Terraform-side settings can change here, and the
+	// computed attributes unknown in the plan are kept from the state.
+	var data, state DeploymentResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	data.Status = state.Status
+	data.CreatedAt = state.CreatedAt
+	data.FinishedAt = state.FinishedAt
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, data.identity())...)
+}
+
+func (r *DeploymentResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	// Deployments cannot be undone, so there is nothing to delete.
+}
+
+func (r *DeploymentResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	var identity DeploymentIdentityModel
+	if req.ID != "" {
+		env, id, ok := strings.Cut(req.ID, "/")
+		if !ok || env == "" || id == "" {
+			resp.Diagnostics.AddError("Invalid Import ID", fmt.Sprintf("Expected an id in the form of {env}/{deployment_id}, got %q", req.ID))
+			return
+		}
+		identity.Env, identity.ID = types.StringValue(env), types.StringValue(id)
+	} else {
+		resp.Diagnostics.Append(req.Identity.Get(ctx, &identity)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("env"), identity.Env)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), identity.ID)...)
+}
+
+func (m *DeploymentResourceModel) set(deploy *Deployment) {
+	m.ID = types.StringValue(deploy.ID)
+	// Keep an abbreviated commit as configured, rather than the full SHA
+	// the platform resolved it to.
+	if commit := m.Commit.ValueString(); commit == "" || !strings.HasPrefix(deploy.Commit, commit) {
+		m.Commit = types.StringValue(deploy.Commit)
+	}
+	if deploy.Branch != "" {
+		m.Branch = types.StringValue(deploy.Branch)
+	}
+	m.Status = types.StringValue(deploy.Status)
+	m.CreatedAt = timeValue(&deploy.CreatedAt)
+	m.FinishedAt = timeValue(deploy.FinishedAt)
+}
+
+func (m *DeploymentResourceModel) identity() *DeploymentIdentityModel {
+	return &DeploymentIdentityModel{Env: m.Env, ID: m.ID}
+}
+
+// timeValue formats t in RFC 3339 format, or returns null if t is not set.
+func timeValue(t *time.Time) types.String {
+	if t == nil || t.IsZero() {
+		return types.StringNull()
+	}
+	return types.StringValue(t.Format(time.RFC3339))
+}
+
+// waitForDeployment polls deploy until it is done, calling onStatus
+// (if non-nil) whenever its status changes.
+func waitForDeployment(ctx context.Context, client PlatformClient, envName string, deploy *Deployment, onStatus func(*Deployment)) error {
+	status := deploy.Status
+	return waitFor(ctx, func(ctx context.Context) (bool, error) {
+		err := client.Call(ctx, "GET", deploymentsPath(client.AppSlug(), envName, deploy.ID), nil, deploy)
+		if err != nil {
+			return false, err
+		}
+		if deploy.Status != status {
+			status = deploy.Status
+			tflog.Debug(ctx, "deployment status changed", map[string]interface{}{
+				"deployment_id": deploy.ID,
+				"status":        deploy.Status,
+			})
+			if onStatus != nil {
+				onStatus(deploy)
+			}
+		}
+		return deploy.Done(), nil
+	})
+}
+
+func deploymentFailure(deploy *Deployment) string {
+	msg := fmt.Sprintf("Deployment %s of commit %s finished with status %q", deploy.ID, deploy.Commit, deploy.Status)
+	if deploy.Error != "" {
+		msg += ": " + deploy.Error
+	}
+	return msg
+}
@@ -0,0 +1,124 @@
+package provider
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
+)
+
+func TestDeploymentResource(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testDeploymentResourceConfig, "eks", `commit = "abc123"`, "1m"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("encore_deployment.deploy", "id", "deploy_eks_1"),
+					resource.TestCheckResourceAttr("encore_deployment.deploy", "env", "eks"),
+					resource.TestCheckResourceAttr("encore_deployment.deploy", "commit", "abc123"),
+					resource.TestCheckNoResourceAttr("encore_deployment.deploy", "branch"),
+					resource.TestCheckResourceAttr("encore_deployment.deploy", "status", "success"),
+					resource.TestCheckResourceAttr("encore_deployment.deploy", "created_at", "2024-01-02T03:04:05Z"),
+					resource.TestCheckResourceAttr("encore_deployment.deploy", "finished_at", "2024-01-02T03:05:05Z"),
+				),
+			},
+			{
+				ResourceName:            "encore_deployment.deploy",
+				ImportState:             true,
+				ImportStateId:           "eks/deploy_eks_1",
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"timeouts"},
+			},
+			{
+				Config: fmt.Sprintf(testDeploymentResourceConfig, "eks", `commit = "abc123"`, "2m"),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("encore_deployment.deploy", plancheck.ResourceActionUpdate),
+					},
+				},
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("encore_deployment.deploy", "id", "deploy_eks_1"),
+					resource.TestCheckResourceAttr("encore_deployment.deploy", "timeouts.create", "2m"),
+					resource.TestCheckResourceAttr("encore_deployment.deploy", "status", "success"),
+					resource.TestCheckResourceAttr("encore_deployment.deploy", "created_at", "2024-01-02T03:04:05Z"),
+					resource.TestCheckResourceAttr("encore_deployment.deploy", "finished_at", "2024-01-02T03:05:05Z"),
+				),
+			},
+			{
+				Config: fmt.Sprintf(testDeploymentResourceConfig, "eks", `branch = "main"`, "1m"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("encore_deployment.deploy", "id", "deploy_eks_2"),
+					resource.TestCheckResourceAttr("encore_deployment.deploy", "commit", "head-of-main"),
+					resource.TestCheckResourceAttr("encore_deployment.deploy", "branch", "main"),
+					resource.TestCheckResourceAttr("encore_deployment.deploy", "status", "success"),
+				),
+			},
+			{
+				// The imported branch must match the configured one, or the next plan would deploy again.
+				ResourceName:            "encore_deployment.deploy",
+				ImportState:             true,
+				ImportStateId:           "eks/deploy_eks_2",
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"timeouts"},
+			},
+		},
+	})
+}
+
+func TestDeploymentResourceAbbreviatedCommit(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				// The platform resolves the commit to its full SHA, which
+				// must not replace the deployment on the next plan.
+				Config: fmt.Sprintf(testDeploymentResourceConfig, "gke", `commit = "c0ffee5"`, "1m"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("encore_deployment.deploy", "commit", "c0ffee5"),
+					resource.TestCheckResourceAttr("encore_deployment.deploy", "status", "success"),
+				),
+			},
+			{
+				Config: fmt.Sprintf(testDeploymentResourceConfig, "gke", `commit = "c0ffee5"`, "1m"),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectEmptyPlan(),
+					},
+				},
+			},
+		},
+	})
+}
+
+func TestDeploymentResourceFailure(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      fmt.Sprintf(testDeploymentResourceConfig, "cloudrun", "commit = \"abc123\"\nbranch = \"main\"", "1m"),
+				ExpectError: regexp.MustCompile("Exactly one of `commit` and `branch` must be set"),
+			},
+			{
+				Config:      fmt.Sprintf(testDeploymentResourceConfig, "cloudrun", `commit = "bad123"`, "1m"),
+				ExpectError: regexp.MustCompile(`finished with status "failure":\s+service api failed health checks`),
+			},
+		},
+	})
+}
+
+const testDeploymentResourceConfig = `
+provider "encore" {
+	auth_key = "test"
+	env = "%s"
+}
+
+resource "encore_deployment" "deploy" {
+	%s
+	timeouts = {
+		create = "%s"
+	}
+}
+`
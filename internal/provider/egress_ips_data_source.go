@@ -0,0 +1,195 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// egressTypeRef is the type of the needs satisfied by the NAT gateways of an environment.
+const egressTypeRef TypeRef = "need.Egress"
+
+var (
+	_ datasource.DataSourceWithConfigure = &EgressIPsDataSource{}
+	_ NeedsDataSource                    = &EgressIPsDataSource{}
+)
+
+func NewEgressIPs() datasource.DataSource {
+	return &EgressIPsDataSource{}
+}
+
+type EgressIPsDataSource struct {
+	needs *NeedsData
+}
+
+type AWSNATGateway struct {
+	Arn          string
+	PublicIp     string
+	AllocationId string
+	Subnet       AWSSubnet
+}
+
+func (a *AWSNATGateway) GetDocs() map[string]string {
+	return map[string]string{
+		"arn":           "The [ARN](https://docs.aws.amazon.com/IAM/latest/UserGuide/reference-arns.html) of the NAT gateway",
+		"public_ip":     "The public IP address of the NAT gateway",
+		"allocation_id": "The allocation id of the [Elastic IP address](https://docs.aws.amazon.com/vpc/latest/userguide/vpc-eips.html) of the NAT gateway",
+		"subnet":        "The public subnet of the NAT gateway",
+	}
+}
+
+type GCPCloudNAT struct {
+	Name      string
+	Router    GCPRouter
+	Addresses []GCPAddress
+}
+
+func (a *GCPCloudNAT) GetDocs() map[string]string {
+	return map[string]string{
+		"name":      "The name of the [Cloud NAT](https://cloud.google.com/nat/docs/overview) gateway",
+		"router":    "The Cloud Router the NAT gateway is configured on",
+		"addresses": "The external IP addresses of the NAT gateway",
+	}
+}
+
+type GCPRouter struct {
+	SelfLink string `tf:"id"`
+	Network  GCPNetwork
+}
+
+func (a *GCPRouter) GetDocs() map[string]string {
+	return map[string]string{
+		"id":      "The [id](https://cloud.google.com/apis/design/resource_names#relative_resource_name) in the form of `projects/{project}/regions/{region}/routers/{router}`",
+		"network": "The network of the router",
+	}
+}
+
+type GCPAddress struct {
+	SelfLink string `tf:"id"`
+	Address  string
+}
+
+func (a *GCPAddress) GetDocs() map[string]string {
+	return map[string]string{
+		"id":      "The [id](https://cloud.google.com/apis/design/resource_names#relative_resource_name) in the form of `projects/{project}/regions/{region}/addresses/{address}`, or `projects/{project}/global/addresses/{address}` for global addresses",
+		"address": "The IP address",
+	}
+}
+
+func (d *EgressIPsDataSource) GetTypeRef() TypeRef {
+	return egressTypeRef
+}
+
+func (d *EgressIPsDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_egress_ips"
+}
+
+func (d *EgressIPsDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	awsNATGateways, diags := getAttribute(reflect.TypeOf([]AWSNATGateway{}), "The AWS NAT gateways of the environment, sorted by name. Set if the environment is provisioned on AWS.")
+	resp.Diagnostics.Append(diags...)
+	gcpCloudNATs, diags := getAttribute(reflect.TypeOf([]GCPCloudNAT{}), "The GCP Cloud NAT gateways of the environment, sorted by name. Set if the environment is provisioned on GCP.")
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "The public IP addresses that an Encore environment uses for outbound traffic, e.g. to allowlist it with third parties",
+		Attributes: map[string]schema.Attribute{
+			"env": schema.StringAttribute{
+				MarkdownDescription: "The environment. Defaults to the provider environment",
+				Optional:            true,
+			},
+			"app": schema.StringAttribute{
+				MarkdownDescription: "The slug of the app of the environment. Defaults to the provider app",
+				Optional:            true,
+			},
+			"ips": schema.ListAttribute{
+				MarkdownDescription: "The egress IP addresses of the environment, of all its NAT gateways",
+				ElementType:         types.StringType,
+				Computed:            true,
+			},
+			"aws_nat_gateways": awsNATGateways,
+			"gcp_cloud_nats":   gcpCloudNATs,
+		},
+	}
+}
+
+func (d *EgressIPsDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	needs, ok := req.ProviderData.(*NeedsData)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *NeedsData, received %T", req.ProviderData),
+		)
+		return
+	}
+
+	d.needs = needs
+}
+
+func (d *EgressIPsDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	if readUnknown(ctx, req, resp, "env", "app") {
+		return
+	}
+	var envName, appSlug types.String
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("env"), &envName)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("app"), &appSlug)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if envName.ValueString() == "" {
+		envName = types.StringValue(d.needs.defaultEnv)
+	}
+	if appSlug.ValueString() == "" {
+		appSlug = types.StringValue(d.needs.client.AppSlug())
+	}
+
+	needs, diags := d.needs.List(ctx, egressTypeRef, appSlug.ValueString(), envName.ValueString())
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ips := []string{}
+	var awsNATGateways []AWSNATGateway
+	var gcpCloudNATs []GCPCloudNAT
+	for _, n := range needs {
+		if n.Satisfier == nil {
+			continue
+		}
+		switch n.Satisfier.Type {
+		case "AWSNATGateway":
+			awsNATGateways = append(awsNATGateways, n.Satisfier.AWSNATGateway)
+			ips = append(ips, n.Satisfier.AWSNATGateway.PublicIp)
+		case "GCPCloudNAT":
+			gcpCloudNATs = append(gcpCloudNATs, n.Satisfier.GCPCloudNAT)
+			for _, a := range n.Satisfier.GCPCloudNAT.Addresses {
+				ips = append(ips, a.Address)
+			}
+		}
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("env"), envName)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("app"), appSlug)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("ips"), ips)...)
+	for name, v := range map[string]interface{}{
+		"aws_nat_gateways": awsNATGateways,
+		"gcp_cloud_nats":   gcpCloudNATs,
+	} {
+		val, diags := getValue(reflect.ValueOf(v))
+		resp.Diagnostics.Append(diags...)
+		if diags.HasError() {
+			return
+		}
+		resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root(name), val)...)
+	}
+}
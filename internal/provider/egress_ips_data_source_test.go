@@ -0,0 +1,78 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func testAWSEgressIPs(natPrefix string, ips ...string) resource.TestCheckFunc {
+	return resource.ComposeAggregateTestCheckFunc(
+		resource.TestCheckResourceAttr("data.encore_egress_ips.egress", "ips.#", "2"),
+		resource.TestCheckResourceAttr("data.encore_egress_ips.egress", "ips.0", ips[0]),
+		resource.TestCheckResourceAttr("data.encore_egress_ips.egress", "ips.1", ips[1]),
+		resource.TestCheckResourceAttr("data.encore_egress_ips.egress", "aws_nat_gateways.#", "2"),
+		resource.TestCheckResourceAttr("data.encore_egress_ips.egress", "aws_nat_gateways.0.arn", "arn:aws:ec2:region:account:natgateway/nat-"+natPrefix+"a1"),
+		resource.TestCheckResourceAttr("data.encore_egress_ips.egress", "aws_nat_gateways.0.public_ip", ips[0]),
+		resource.TestCheckResourceAttr("data.encore_egress_ips.egress", "aws_nat_gateways.0.allocation_id", "eipalloc-"+natPrefix+"a1"),
+		resource.TestCheckResourceAttr("data.encore_egress_ips.egress", "aws_nat_gateways.0.subnet.az", "us-east-1a"),
+		resource.TestCheckResourceAttr("data.encore_egress_ips.egress", "aws_nat_gateways.1.subnet.arn", "arn:aws:ec2:region:account:subnet/subnet-public-us-east-1b"),
+		resource.TestCheckResourceAttr("data.encore_egress_ips.egress", "aws_nat_gateways.1.subnet.vpc.id", "vpc"),
+		resource.TestCheckResourceAttr("data.encore_egress_ips.egress", "gcp_cloud_nats.#", "0"),
+	)
+}
+
+func testGCPEgressIPs(region string) resource.TestCheckFunc {
+	return resource.ComposeAggregateTestCheckFunc(
+		resource.TestCheckResourceAttr("data.encore_egress_ips.egress", "ips.#", "2"),
+		resource.TestCheckResourceAttr("data.encore_egress_ips.egress", "ips.0", "34.95.10.20"),
+		resource.TestCheckResourceAttr("data.encore_egress_ips.egress", "ips.1", "34.95.10.21"),
+		resource.TestCheckResourceAttr("data.encore_egress_ips.egress", "gcp_cloud_nats.#", "1"),
+		resource.TestCheckResourceAttr("data.encore_egress_ips.egress", "gcp_cloud_nats.0.name", "app-env-nat"),
+		resource.TestCheckResourceAttr("data.encore_egress_ips.egress", "gcp_cloud_nats.0.router.id", "projects/app-env/regions/"+region+"/routers/app-env-router"),
+		resource.TestCheckResourceAttr("data.encore_egress_ips.egress", "gcp_cloud_nats.0.router.network.id", "projects/app-env/global/networks/default"),
+		resource.TestCheckResourceAttr("data.encore_egress_ips.egress", "gcp_cloud_nats.0.addresses.#", "2"),
+		resource.TestCheckResourceAttr("data.encore_egress_ips.egress", "gcp_cloud_nats.0.addresses.1.id", "projects/app-env/regions/"+region+"/addresses/app-env-nat-1"),
+		resource.TestCheckResourceAttr("data.encore_egress_ips.egress", "gcp_cloud_nats.0.addresses.1.address", "34.95.10.21"),
+		resource.TestCheckResourceAttr("data.encore_egress_ips.egress", "aws_nat_gateways.#", "0"),
+	)
+}
+
+func TestEgressIPsDataSource(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testV6ProviderFactories,
+		Steps: []resource.TestStep{
+			testStepForEnv(
+				"eks",
+				testEgressIPsDataSourceConfig,
+				resource.TestCheckResourceAttr("data.encore_egress_ips.egress", "env", "eks"),
+				testAWSEgressIPs("0", "3.210.15.1", "3.210.15.2"),
+			),
+			testStepForEnv(
+				"fargate",
+				testEgressIPsDataSourceConfig,
+				testAWSEgressIPs("1", "54.164.20.1", "54.164.20.2"),
+			),
+			testStepForEnv(
+				"cloudrun",
+				testEgressIPsDataSourceConfig,
+				testGCPEgressIPs("northamerica-northeast1"),
+			),
+			testStepForEnv(
+				"gke",
+				testEgressIPsDataSourceConfig,
+				testGCPEgressIPs("us-central1"),
+			),
+		},
+	})
+}
+
+const testEgressIPsDataSourceConfig = `
+provider "encore" {
+	auth_key = "test"
+	env = "%s"
+}
+
+data "encore_egress_ips" "egress" {
+}
+`
@@ -27,6 +27,9 @@ type SatisfierQuery struct {
 	Service `graphql:"... on Service"`
 
 	Gateway `graphql:"... on Gateway"`
+
+	AWSNATGateway AWSNATGateway `graphql:"... on AWSNATGateway" tf:"aws_nat_gateway"`
+	GCPCloudNAT   GCPCloudNAT   `graphql:"... on GCPCloudNAT" tf:"gcp_cloud_nat"`
 }
 
 func (a *SatisfierQuery) GetDocs() (attrDesc map[string]string) {
@@ -36,7 +39,10 @@ func (a *SatisfierQuery) GetDocs() (attrDesc map[string]string) {
 	}
 }
 
-var _ datasource.DataSource = &EncoreDataSource{}
+var (
+	_ datasource.DataSource = &EncoreDataSource{}
+	_ NeedsDataSource       = &EncoreDataSource{}
+)
 
 func NewEncoreDataSource(typeRef TypeRef, name, desc string, fragments ...string) datasource.DataSource {
 	return &EncoreDataSource{
@@ -53,6 +59,10 @@ type EncoreDataSource struct {
 	schema  schema.Schema
 }
 
+func (d *EncoreDataSource) GetTypeRef() TypeRef {
+	return d.typeRef
+}
+
 func (d *EncoreDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
 	resp.TypeName = req.ProviderTypeName + "_" + d.name
 }
@@ -82,5 +92,8 @@ func (d *EncoreDataSource) Configure(ctx context.Context, req datasource.Configu
 }
 
 func (d *EncoreDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	if readUnknown(ctx, req, resp, "name", "env", "app") {
+		return
+	}
 	resp.Diagnostics.Append(d.needs.SetValue(ctx, d.typeRef, req.Config, &resp.State)...)
 }
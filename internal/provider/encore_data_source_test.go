@@ -1,9 +1,18 @@
 package provider
 
 import (
+	"context"
 	"fmt"
+	"slices"
+	"testing"
 
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
 )
 
 func testAWSSubnets(res, prefix string) resource.TestCheckFunc {
@@ -21,3 +30,163 @@ func testStepForEnv(env, cfg string, fns ...resource.TestCheckFunc) resource.Tes
 		Check:  resource.ComposeAggregateTestCheckFunc(fns...),
 	}
 }
+
+func TestEncoreDataSourceApp(t *testing.T) {
+	testPlatform.mu.Lock()
+	testPlatform.needsQueries = nil
+	testPlatform.mu.Unlock()
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+provider "encore" {
+	auth_key = "test"
+	app      = "app-a"
+	env      = "eks"
+}
+
+data "encore_service" "a" {
+	name = "cache"
+}
+
+data "encore_cache" "a" {
+	name = "cache"
+}
+
+data "encore_service" "b" {
+	name = "cache"
+	app  = "app-b"
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.encore_service.a", "app", "app-a"),
+					resource.TestCheckResourceAttr("data.encore_service.b", "app", "app-b"),
+					resource.TestCheckResourceAttr("data.encore_service.b", "env", "eks"),
+					testEKSService("data.encore_service.b", "cache"),
+					func(*terraform.State) error {
+						testPlatform.mu.Lock()
+						defer testPlatform.mu.Unlock()
+						// Both apps are queried, in the provider environment.
+						queries := slices.Clone(testPlatform.needsQueries)
+						slices.Sort(queries)
+						if compacted := slices.Compact(slices.Clone(queries)); !slices.Equal(compacted, []string{"app-a/eks", "app-b/eks"}) {
+							return fmt.Errorf("got needs queries %v, want app-a/eks and app-b/eks", queries)
+						}
+						return nil
+					},
+				),
+			},
+		},
+	})
+}
+
+func TestEncoreDataSourceUnknownName(t *testing.T) {
+	ctx := context.Background()
+	ds := NewService()
+	var schemaResp datasource.SchemaResponse
+	ds.Schema(ctx, datasource.SchemaRequest{}, &schemaResp)
+	objType := schemaResp.Schema.Type().TerraformType(ctx).(tftypes.Object)
+	vals := map[string]tftypes.Value{}
+	for name, typ := range objType.AttributeTypes {
+		vals[name] = tftypes.NewValue(typ, nil)
+	}
+	vals["name"] = tftypes.NewValue(tftypes.String, tftypes.UnknownValue)
+	vals["env"] = tftypes.NewValue(tftypes.String, "eks")
+	req := datasource.ReadRequest{
+		Config: tfsdk.Config{Raw: tftypes.NewValue(objType, vals), Schema: schemaResp.Schema},
+	}
+	newResp := func() *datasource.ReadResponse {
+		return &datasource.ReadResponse{
+			State: tfsdk.State{Raw: tftypes.NewValue(objType, nil), Schema: schemaResp.Schema},
+		}
+	}
+
+	resp := newResp()
+	req.ClientCapabilities.DeferralAllowed = true
+	ds.Read(ctx, req, resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("got diagnostics %v", resp.Diagnostics)
+	} else if resp.Deferred == nil || resp.Deferred.Reason != datasource.DeferredReasonDataSourceConfigUnknown {
+		t.Errorf("got deferred %v, want data source config unknown", resp.Deferred)
+	}
+
+	resp = newResp()
+	req.ClientCapabilities.DeferralAllowed = false
+	ds.Read(ctx, req, resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("got diagnostics %v", resp.Diagnostics)
+	}
+	var state map[string]tftypes.Value
+	if err := resp.State.Raw.As(&state); err != nil {
+		t.Fatal(err)
+	}
+	for attr, wantKnown := range map[string]bool{"name": false, "env": true, "k8s_deployment": false, "gcp_cloud_run": false} {
+		if known := state[attr].IsKnown(); known != wantKnown {
+			t.Errorf("got %s known = %v, want %v", attr, known, wantKnown)
+		}
+	}
+}
+
+func TestEncoreDataSourceUnknownNameApply(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+provider "encore" {
+	auth_key = "test"
+	env      = "eks"
+}
+
+resource "terraform_data" "name" {
+	input = "cache"
+}
+
+data "encore_service" "service" {
+	name = terraform_data.name.output
+}
+`,
+				Check: testEKSService("data.encore_service.service", "cache"),
+			},
+		},
+	})
+}
+
+func TestEncoreDataSourceForEachName(t *testing.T) {
+	const service = `data.encore_service.service["cache"]`
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+provider "encore" {
+	auth_key = "test"
+	env      = "eks"
+}
+
+resource "terraform_data" "name" {
+	for_each = toset(["cache"])
+	input    = each.key
+}
+
+data "encore_service" "service" {
+	for_each = terraform_data.name
+	name     = each.value.output
+}
+`,
+				// The state of resources with for_each cannot be checked.
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PostApplyPreRefresh: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction(service, plancheck.ResourceActionRead),
+						plancheck.ExpectUnknownValue(service, tfjsonpath.New("k8s_deployment")),
+						plancheck.ExpectUnknownValue(service, tfjsonpath.New("gcp_cloud_run")),
+					},
+				},
+			},
+		},
+	})
+}
@@ -0,0 +1,773 @@
+package provider
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+// fakePlatform is an in-process Encore Platform API for tests. It serves
+// logins, token refreshes, the REST endpoints of a testPlatformState and
+// a GraphQL endpoint that evaluates queries against the environments in
+// testdata, so that tests exercise the real PlatformClientImpl.
+type fakePlatform struct {
+	*httptest.Server
+	state *testPlatformState
+
+	mu sync.Mutex
+	// tokenTTL is the lifetime of issued access tokens.
+	tokenTTL time.Duration
+	// latency delays every response.
+	latency time.Duration
+	// faults are the errors to respond to matching requests with.
+	faults []*fakeFault
+	// schema is the GraphQL schema served for introspection, which is
+	// not supported if nil.
+	schema   platformSchema
+	rootType string
+
+	issued            int
+	tokens            map[string]time.Time // the expiry of each issued access token
+	refreshTokens     map[string]string    // the app slug of each issued refresh token
+	logins, refreshes int
+	envs              map[string]map[string]interface{} // the fixture of each env, by name
+}
+
+// fakeFault is an error injected into the responses of a fakePlatform.
+type fakeFault struct {
+	// Path is the request path to fail, or all paths if empty.
+	Path string
+	// Status is the HTTP status code to respond with, for REST endpoints.
+	Status int
+	// Code is the platform error code to respond with, for REST endpoints.
+	Code string
+	// GraphQL is the GraphQL error message to respond with, for /graphql.
+	GraphQL string
+	// Times is the number of requests to fail, or every request if zero.
+	Times int
+}
+
+func newFakePlatform(state *testPlatformState) *fakePlatform {
+	p := &fakePlatform{
+		state:         state,
+		tokenTTL:      time.Hour,
+		tokens:        map[string]time.Time{},
+		refreshTokens: map[string]string{},
+		envs:          map[string]map[string]interface{}{},
+	}
+	p.Server = httptest.NewServer(http.HandlerFunc(p.serveHTTP))
+	return p
+}
+
+// testFakePlatform is the fake platform backing testV6ProviderFactories.
+// It serves the REST endpoints from testPlatform.
+var testFakePlatform = sync.OnceValue(func() *fakePlatform {
+	return newFakePlatform(testPlatform)
+})
+
+// factories returns provider factories for acceptance tests whose
+// platform clients talk to p.
+func (p *fakePlatform) factories() map[string]func() (tfprotov6.ProviderServer, error) {
+	return map[string]func() (tfprotov6.ProviderServer, error){
+		"encore": providerserver.NewProtocol6WithError(newForTest("test", p.newClient)()),
+	}
+}
+
+// newClient returns a PlatformClientImpl for p.
+func (p *fakePlatform) newClient(version string, opts ClientOptions) PlatformClient {
+	opts.BaseURL = p.URL
+	return NewPlatformClient(version, opts)
+}
+
+// inject fails the requests matching f.
+func (p *fakePlatform) inject(f fakeFault) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.faults = append(p.faults, &f)
+}
+
+// setLatency delays every response by d.
+func (p *fakePlatform) setLatency(d time.Duration) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.latency = d
+}
+
+// setTokenTTL sets the lifetime of access tokens issued from now on.
+func (p *fakePlatform) setTokenTTL(d time.Duration) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.tokenTTL = d
+}
+
+// expireTokens expires all access tokens issued so far, as if they had
+// been revoked. Clients must refresh them to make further requests.
+func (p *fakePlatform) expireTokens() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for token := range p.tokens {
+		p.tokens[token] = time.Time{}
+	}
+}
+
+// setSchema serves schema for introspection queries.
+func (p *fakePlatform) setSchema(schema platformSchema, rootType string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.schema, p.rootType = schema, rootType
+}
+
+// counts returns the number of logins and token refreshes so far.
+func (p *fakePlatform) counts() (logins, refreshes int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.logins, p.refreshes
+}
+
+func (p *fakePlatform) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	p.mu.Lock()
+	latency := p.latency
+	var fault *fakeFault
+	for _, f := range p.faults {
+		if f.Path == "" || f.Path == r.URL.Path {
+			fault = f
+			break
+		}
+	}
+	if fault != nil && fault.Times > 0 {
+		if fault.Times--; fault.Times == 0 {
+			p.faults = slices.DeleteFunc(p.faults, func(f *fakeFault) bool { return f == fault })
+		}
+	}
+	p.mu.Unlock()
+
+	if latency > 0 {
+		select {
+		case <-time.After(latency):
+		case <-r.Context().Done():
+			return
+		}
+	}
+	switch {
+	case fault != nil && r.URL.Path == "/graphql" && fault.GraphQL != "":
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"errors": []map[string]string{{"message": fault.GraphQL}},
+		})
+	case fault != nil:
+		status := fault.Status
+		if status == 0 {
+			status = http.StatusInternalServerError
+		}
+		writeJSON(w, status, map[string]interface{}{"ok": false, "error": Error{Code: fault.Code}})
+	case r.URL.Path == "/login/auth-key":
+		var params struct {
+			AuthKey string `json:"auth_key"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
+			writePlatformError(w, err)
+			return
+		}
+		p.login(w, "auth_key:"+params.AuthKey)
+	case r.URL.Path == "/login/oidc":
+		var params struct {
+			IDToken  string `json:"id_token"`
+			Audience string `json:"audience"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
+			writePlatformError(w, err)
+			return
+		}
+		p.login(w, "oidc:"+params.IDToken+"@"+params.Audience)
+	case r.URL.Path == "/login/oauth:refresh-token":
+		p.refresh(w, r.FormValue("refresh_token"))
+	case !p.authorized(r):
+		writePlatformError(w, Error{HTTPCode: http.StatusUnauthorized, Code: "unauthenticated"})
+	case r.URL.Path == "/graphql":
+		p.serveGraphQL(w, r)
+	default:
+		var reqParams interface{}
+		if r.ContentLength != 0 {
+			if err := json.NewDecoder(r.Body).Decode(&reqParams); err != nil {
+				writePlatformError(w, err)
+				return
+			}
+		}
+		path := r.URL.EscapedPath()
+		if r.URL.RawQuery != "" {
+			path += "?" + r.URL.RawQuery
+		}
+		resp, err := p.state.handle(r.Method, path, reqParams)
+		if err != nil {
+			writePlatformError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{"ok": true, "data": resp})
+	}
+}
+
+// login logs in with creds, responding with a new token for the app "test".
+func (p *fakePlatform) login(w http.ResponseWriter, creds string) {
+	if err := p.state.login(creds); err != nil {
+		writePlatformError(w, err)
+		return
+	}
+	// Auth keys of users are not scoped to an app.
+	appSlug := "test"
+	if strings.HasPrefix(creds, "auth_key:user-") {
+		appSlug = ""
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.logins++
+	access, refresh, expiry := p.issue(appSlug)
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"ok": true,
+		"data": map[string]interface{}{
+			"token": map[string]interface{}{
+				"access_token":  access,
+				"refresh_token": refresh,
+				"token_type":    "Bearer",
+				"expiry":        expiry,
+			},
+			"app_slug": appSlug,
+		},
+	})
+}
+
+// refresh responds with a new token in exchange for a refresh token.
+func (p *fakePlatform) refresh(w http.ResponseWriter, refreshToken string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	app, ok := p.refreshTokens[refreshToken]
+	if !ok {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_grant"})
+		return
+	}
+	delete(p.refreshTokens, refreshToken)
+	p.refreshes++
+	access, refresh, expiry := p.issue(app)
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"access_token":  access,
+		"refresh_token": refresh,
+		"token_type":    "Bearer",
+		// An expires_in of zero means the token does not expire.
+		"expires_in": max(1, int(time.Until(expiry).Seconds()+0.5)),
+	})
+}
+
+// issue issues an access token and a refresh token for app.
+// It must be called with p.mu held.
+func (p *fakePlatform) issue(app string) (access, refresh string, expiry time.Time) {
+	p.issued++
+	access = fmt.Sprintf("access-%d", p.issued)
+	refresh = fmt.Sprintf("refresh-%d", p.issued)
+	expiry = time.Now().Add(p.tokenTTL).UTC().Truncate(time.Second)
+	p.tokens[access] = expiry
+	p.refreshTokens[refresh] = app
+	return access, refresh, expiry
+}
+
+// lastIssued returns the access token issued last and its expiry.
+func (p *fakePlatform) lastIssued() (access string, expiry time.Time) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	access = fmt.Sprintf("access-%d", p.issued)
+	return access, p.tokens[access]
+}
+
+// authorized reports whether r carries an unexpired access token.
+func (p *fakePlatform) authorized(r *http.Request) bool {
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok {
+		return false
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	expiry, ok := p.tokens[token]
+	return ok && time.Now().Before(expiry)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writePlatformError(w http.ResponseWriter, err error) {
+	var e Error
+	if !errors.As(err, &e) {
+		e = Error{HTTPCode: http.StatusBadRequest, Code: "invalid_argument", Detail: json.RawMessage(fmt.Sprintf("%q", err.Error()))}
+	}
+	writeJSON(w, e.HTTPCode, map[string]interface{}{"ok": false, "error": e})
+}
+
+// serveGraphQL evaluates a GraphQL query. The query root has the fields
+// app(slug), whose env(name) are the fixtures in testdata and whose
+// needs(sel:{typeRefs}) are those of the fixture with the given type
+// refs, and __schema if a schema is set.
+func (p *fakePlatform) serveGraphQL(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Query     string                 `json:"query"`
+		Variables map[string]interface{} `json:"variables"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]interface{}{"errors": []map[string]string{{"message": err.Error()}}})
+		return
+	}
+	sels, err := parseFakeQuery(req.Query)
+	if err == nil {
+		var data interface{}
+		data, err = evalFakeSelection(p.root(), sels, req.Variables)
+		if err == nil {
+			writeJSON(w, http.StatusOK, map[string]interface{}{"data": data})
+			return
+		}
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"data":   nil,
+		"errors": []map[string]string{{"message": err.Error()}},
+	})
+}
+
+// fakeResolver resolves a GraphQL field with arguments.
+type fakeResolver func(args map[string]interface{}) (interface{}, error)
+
+func (p *fakePlatform) root() map[string]interface{} {
+	return map[string]interface{}{
+		"__schema": fakeResolver(func(map[string]interface{}) (interface{}, error) {
+			return p.introspection()
+		}),
+		"app": fakeResolver(func(args map[string]interface{}) (interface{}, error) {
+			app, _ := args["slug"].(string)
+			return map[string]interface{}{
+				"env": fakeResolver(func(args map[string]interface{}) (interface{}, error) {
+					envName, _ := args["name"].(string)
+					env, err := p.env(envName)
+					if err != nil {
+						return nil, err
+					}
+					return map[string]interface{}{
+						"name": envName,
+						"needs": fakeResolver(func(args map[string]interface{}) (interface{}, error) {
+							return p.needs(app, envName, env, args["typeRefs"])
+						}),
+					}, nil
+				}),
+			}, nil
+		}),
+	}
+}
+
+// env returns the fixture of the environment named name.
+func (p *fakePlatform) env(name string) (map[string]interface{}, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if env, ok := p.envs[name]; ok {
+		return env, nil
+	}
+	if !slices.Contains(testFixtureEnvs, name) {
+		return nil, errors.New("env not found")
+	}
+	data, err := os.ReadFile(fmt.Sprintf("testdata/%s.json", name))
+	if err != nil {
+		return nil, err
+	}
+	var fixture struct {
+		Data struct {
+			App struct {
+				Env map[string]interface{}
+			}
+		}
+	}
+	if err := json.Unmarshal(data, &fixture); err != nil {
+		return nil, fmt.Errorf("testdata/%s.json: %v", name, err)
+	}
+	p.envs[name] = fixture.Data.App.Env
+	return fixture.Data.App.Env, nil
+}
+
+// needs returns the needs of env with one of typeRefs, recording the query.
+func (p *fakePlatform) needs(app, envName string, env map[string]interface{}, typeRefs interface{}) (interface{}, error) {
+	refs, ok := typeRefs.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("invalid typeRefs %v", typeRefs)
+	}
+	p.state.mu.Lock()
+	p.state.needsQueries = append(p.state.needsQueries, app+"/"+envName)
+	p.state.mu.Unlock()
+
+	all, _ := env["needs"].([]interface{})
+	var needs []interface{}
+	for _, need := range all {
+		if need, ok := need.(map[string]interface{}); ok && slices.Contains(refs, need["typeRef"]) {
+			needs = append(needs, need)
+		}
+	}
+	return needs, nil
+}
+
+// introspection returns the __schema of the introspection query.
+func (p *fakePlatform) introspection() (interface{}, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.schema == nil {
+		return nil, errors.New("introspection is disabled")
+	}
+	var types []interface{}
+	for name, st := range p.schema {
+		var fields, possibleTypes []interface{}
+		for fieldName, fieldType := range st.Fields {
+			fields = append(fields, map[string]interface{}{
+				"name": fieldName,
+				"type": map[string]interface{}{"name": fieldType, "ofType": nil},
+			})
+		}
+		for _, pt := range st.PossibleTypes {
+			possibleTypes = append(possibleTypes, map[string]interface{}{"name": pt})
+		}
+		types = append(types, map[string]interface{}{
+			"name":          name,
+			"fields":        fields,
+			"possibleTypes": possibleTypes,
+		})
+	}
+	return map[string]interface{}{
+		"queryType": map[string]interface{}{"name": p.rootType},
+		"types":     types,
+	}, nil
+}
+
+// fakeSelection is a field or inline fragment of a GraphQL selection set.
+type fakeSelection struct {
+	// Name is the name of the field, or the type condition of a fragment.
+	Name     string
+	Fragment bool
+	// Args are the raw arguments of the field, without parentheses.
+	Args string
+	Sel  []fakeSelection
+}
+
+var fakeQueryToken = regexp.MustCompile(`\.\.\.\s*on\s+(\w+)|[\w$]+|[{}(),]|\S`)
+
+// parseFakeQuery parses the selection set of a query in the form written
+// by the GraphQL client: an optional operation header followed by fields
+// with arguments, and inline fragments.
+func parseFakeQuery(query string) ([]fakeSelection, error) {
+	// Skip the operation header, up to its selection set.
+	depth := 0
+	for i, c := range query {
+		if c == '(' {
+			depth++
+		} else if c == ')' {
+			depth--
+		} else if c == '{' && depth == 0 {
+			query = query[i:]
+			break
+		}
+	}
+	if !strings.HasPrefix(query, "{") {
+		return nil, fmt.Errorf("invalid query %q", query)
+	}
+
+	var parse func(s string) ([]fakeSelection, string, error)
+	parse = func(s string) ([]fakeSelection, string, error) {
+		s = strings.TrimPrefix(strings.TrimSpace(s), "{")
+		var sels []fakeSelection
+		for {
+			s = strings.TrimLeft(s, " \t\n,")
+			if s == "" {
+				return nil, "", errors.New("unterminated selection set")
+			} else if strings.HasPrefix(s, "}") {
+				return sels, s[1:], nil
+			}
+			tok := fakeQueryToken.FindStringSubmatchIndex(s)
+			var sel fakeSelection
+			if tok[2] >= 0 {
+				sel = fakeSelection{Name: s[tok[2]:tok[3]], Fragment: true}
+			} else {
+				sel = fakeSelection{Name: s[tok[0]:tok[1]]}
+			}
+			s = strings.TrimSpace(s[tok[1]:])
+			if strings.HasPrefix(s, "(") {
+				end := strings.Index(s, ")")
+				if end < 0 {
+					return nil, "", errors.New("unterminated arguments")
+				}
+				sel.Args, s = s[1:end], strings.TrimSpace(s[end+1:])
+			}
+			if strings.HasPrefix(s, "{") {
+				var err error
+				if sel.Sel, s, err = parse(s); err != nil {
+					return nil, "", err
+				}
+			}
+			sels = append(sels, sel)
+		}
+	}
+	sels, rest, err := parse(query)
+	if err == nil && strings.TrimSpace(rest) != "" {
+		err = fmt.Errorf("unexpected %q after query", rest)
+	}
+	return sels, err
+}
+
+var fakeArg = regexp.MustCompile(`(\w+)\s*:\s*(?:\$(\w+)|"([^"]*)")`)
+
+// args returns the arguments of sel, flattening input objects.
+func (sel fakeSelection) args(vars map[string]interface{}) map[string]interface{} {
+	args := map[string]interface{}{}
+	for _, m := range fakeArg.FindAllStringSubmatch(sel.Args, -1) {
+		if m[2] != "" {
+			args[m[1]] = vars[m[2]]
+		} else {
+			args[m[1]] = m[3]
+		}
+	}
+	return args
+}
+
+// evalFakeSelection evaluates the selection set sels on v. Every field
+// selected must be in v, if only as null, and objects selected with
+// inline fragments must name their type with a __typename, like the
+// platform does, so that fixtures cannot pass for other types.
+func evalFakeSelection(v interface{}, sels []fakeSelection, vars map[string]interface{}) (interface{}, error) {
+	switch v := v.(type) {
+	case []interface{}:
+		out := make([]interface{}, len(v))
+		for i, elem := range v {
+			var err error
+			if out[i], err = evalFakeSelection(elem, sels, vars); err != nil {
+				return nil, err
+			}
+		}
+		return out, nil
+	case map[string]interface{}:
+		typename, _ := v["__typename"].(string)
+		out := map[string]interface{}{}
+		for _, sel := range sels {
+			if sel.Fragment {
+				if err := checkFakeType(sel.Name); err != nil {
+					return nil, err
+				} else if typename == "" {
+					return nil, fmt.Errorf("no __typename for fragment on %s in %v", sel.Name, v)
+				} else if sel.Name != typename {
+					continue
+				}
+				fields, err := evalFakeSelection(v, sel.Sel, vars)
+				if err != nil {
+					return nil, err
+				}
+				for name, val := range fields.(map[string]interface{}) {
+					out[name] = val
+				}
+				continue
+			}
+			val, ok := v[sel.Name]
+			if !ok {
+				return nil, fmt.Errorf("unknown field %q on %s", sel.Name, cmp.Or(typename, "object"))
+			}
+			if resolve, ok := val.(fakeResolver); ok {
+				var err error
+				if val, err = resolve(sel.args(vars)); err != nil {
+					return nil, err
+				}
+			}
+			if len(sel.Sel) > 0 && val != nil {
+				var err error
+				if val, err = evalFakeSelection(val, sel.Sel, vars); err != nil {
+					return nil, err
+				}
+			}
+			out[sel.Name] = val
+		}
+		return out, nil
+	default:
+		return v, nil
+	}
+}
+
+// checkFakeType returns an error if name is not a type of the checked-in
+// platform schema, as for a misspelled type in an inline fragment.
+func checkFakeType(name string) error {
+	schema, err := testPlatformSchema()
+	if err != nil {
+		return err
+	}
+	if _, ok := schema.ASTSchema().Types[name]; !ok {
+		return fmt.Errorf("unknown type %q in fragment", name)
+	}
+	return nil
+}
+
+func TestFakePlatform(t *testing.T) {
+	ctx := context.Background()
+	newPlatform := func(t *testing.T) (*fakePlatform, PlatformClient) {
+		platform := newFakePlatform(newTestPlatformState())
+		t.Cleanup(platform.Close)
+		client := platform.newClient("test", ClientOptions{})
+		if err := client.Auth(ctx, "test"); err != nil {
+			t.Fatal(err)
+		}
+		return platform, client
+	}
+
+	t.Run("needs selection", func(t *testing.T) {
+		_, client := newPlatform(t)
+		n := NewNeedsData(client, "eks", []func() datasource.DataSource{NewPubSubTopic})
+		needs, diags := n.envNeeds(ctx, needsKey{app: "test", env: "eks"})
+		if diags.HasError() {
+			t.Fatal(diags)
+		}
+		if got := slices.Collect(maps.Keys(needs)); !slices.Equal(got, []TypeRef{"need.Topic"}) {
+			t.Errorf("got needs of types %v, want only need.Topic", got)
+		}
+		for name, need := range needs["need.Topic"] {
+			if need.Satisfier.AWSSNSTopic.Arn == "" {
+				t.Errorf("got topic %s without an ARN", name)
+			}
+		}
+	})
+
+	t.Run("strict evaluation", func(t *testing.T) {
+		root := map[string]interface{}{
+			"typed":   map[string]interface{}{"__typename": "AWSSNSTopic", "arn": "arn"},
+			"untyped": map[string]interface{}{"arn": "arn"},
+		}
+		for query, want := range map[string]string{
+			"{typed{... on AWSSNSTopic{arn}}}":     "",
+			"{typed{... on AWSSNSTopic{name}}}":    `unknown field "name" on AWSSNSTopic`,
+			"{typed{... on AWSSNSTopik{arn}}}":     `unknown type "AWSSNSTopik"`,
+			"{untyped{... on AWSSNSTopic{arn}}}":   "no __typename",
+			"{untyped{arn},missing{arn}}":          `unknown field "missing"`,
+			"{typed{__typename,arn},untyped{arn}}": "",
+		} {
+			sels, err := parseFakeQuery(query)
+			if err != nil {
+				t.Fatal(err)
+			}
+			_, err = evalFakeSelection(root, sels, nil)
+			if want == "" && err != nil {
+				t.Errorf("%s: got error %v", query, err)
+			} else if want != "" && (err == nil || !strings.Contains(err.Error(), want)) {
+				t.Errorf("%s: got error %v, want %q", query, err, want)
+			}
+		}
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		platform, client := newPlatform(t)
+		n := NewNeedsData(client, "eks", []func() datasource.DataSource{NewPubSubTopic})
+		n.capabilities(ctx)
+		platform.inject(fakeFault{Path: "/graphql", GraphQL: "env not found", Times: 1})
+		if _, diags := n.envNeeds(ctx, needsKey{app: "test", env: "eks"}); !diags.HasError() || diags.Errors()[0].Summary() != "Env not found" {
+			t.Errorf("got diagnostics %v, want env not found", diags)
+		}
+		if _, diags := n.envNeeds(ctx, needsKey{app: "test", env: "eks"}); diags.HasError() {
+			t.Errorf("got diagnostics %v after the fault", diags)
+		}
+
+		platform.inject(fakeFault{Path: "/apps/test/envs/eks/deploys", Status: http.StatusServiceUnavailable, Code: "unavailable"})
+		var e Error
+		if err := client.Call(ctx, "GET", "/apps/test/envs/eks/deploys", nil, nil); !errors.As(err, &e) || e.HTTPCode != http.StatusServiceUnavailable || e.Code != "unavailable" {
+			t.Errorf("got error %v, want unavailable", err)
+		}
+	})
+
+	t.Run("latency", func(t *testing.T) {
+		platform, client := newPlatform(t)
+		platform.setLatency(time.Second)
+		ctx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+		defer cancel()
+		if err := client.Call(ctx, "GET", "/apps/test/envs/eks/deploys", nil, nil); !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("got error %v, want deadline exceeded", err)
+		}
+	})
+
+	t.Run("token expiry", func(t *testing.T) {
+		platform := newFakePlatform(newTestPlatformState())
+		defer platform.Close()
+		// Tokens expiring within seconds are refreshed before each request.
+		platform.setTokenTTL(time.Second)
+		client := platform.newClient("test", ClientOptions{})
+		if err := client.Auth(ctx, "test"); err != nil {
+			t.Fatal(err)
+		}
+		for i := 0; i < 2; i++ {
+			if err := client.Call(ctx, "GET", "/apps/test/envs/eks/deploys", nil, nil); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if logins, refreshes := platform.counts(); logins != 1 || refreshes != 2 {
+			t.Errorf("got %d logins and %d refreshes, want 1 login and 2 refreshes", logins, refreshes)
+		}
+
+		platform.setTokenTTL(time.Hour)
+		if err := client.Call(ctx, "GET", "/apps/test/envs/eks/deploys", nil, nil); err != nil {
+			t.Fatal(err)
+		}
+		platform.expireTokens()
+		var e Error
+		if err := client.Call(ctx, "GET", "/apps/test/envs/eks/deploys", nil, nil); !errors.As(err, &e) || e.Code != "unauthenticated" {
+			t.Errorf("got error %v, want unauthenticated", err)
+		}
+	})
+
+	t.Run("user tokens", func(t *testing.T) {
+		platform := newFakePlatform(newTestPlatformState())
+		defer platform.Close()
+		// Tokens of users are not scoped to an app, also once refreshed.
+		platform.setTokenTTL(time.Second)
+		client := platform.newClient("test", ClientOptions{})
+		if err := client.Auth(ctx, "user-key"); err != nil {
+			t.Fatal(err)
+		} else if app := client.AppSlug(); app != "" {
+			t.Errorf("got app %q, want none", app)
+		}
+		if _, err := client.Token(ctx); err != nil {
+			t.Fatal(err)
+		}
+		platform.mu.Lock()
+		defer platform.mu.Unlock()
+		for refresh, app := range platform.refreshTokens {
+			if app != "" {
+				t.Errorf("got refresh token %s for app %q, want none", refresh, app)
+			}
+		}
+	})
+
+	t.Run("acceptance", func(t *testing.T) {
+		platform := newFakePlatform(newTestPlatformState())
+		defer platform.Close()
+		platform.inject(fakeFault{Path: "/graphql", GraphQL: "internal error"})
+		resource.UnitTest(t, resource.TestCase{
+			ProtoV6ProviderFactories: platform.factories(),
+			Steps: []resource.TestStep{
+				{
+					Config:      fmt.Sprintf(testPubsubSubDataSourceConfig, "eks"),
+					ExpectError: regexp.MustCompile(`Unable\s+to\s+fetch\s+Encore\s+resources`),
+				},
+				{
+					Config:      strings.Replace(fmt.Sprintf(testPubsubSubDataSourceConfig, "eks"), `"test"`, `"invalid"`, 1),
+					ExpectError: regexp.MustCompile(`Authentication\s+Failed`),
+				},
+			},
+		})
+	})
+}
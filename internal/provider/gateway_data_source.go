@@ -13,49 +13,185 @@ func NewGateway() datasource.DataSource {
 }
 
 type Gateway struct {
+	BaseUrl         string
+	Hostnames       []string
 	ComputeInstance `graphql:"compute"`
 	Route           `graphql:"route"`
 	Ingress         `graphql:"ingress"`
 }
 
+func (g *Gateway) GetDocs() map[string]string {
+	return map[string]string{
+		"base_url":  "The public base URL of the gateway, e.g. `https://staging-myapp-x4t2.encr.app`",
+		"hostnames": "The hostnames the gateway serves, including those of custom domains",
+	}
+}
+
 type Ingress struct {
-	K8sIngress K8sIngress         `graphql:"... on K8sIngress"`
-	AwsAlb     AWSAppLoadBalancer `graphql:"... on AWSAppLoadBalancer"`
+	K8sIngress      K8sIngress         `graphql:"... on K8sIngress"`
+	AwsAlb          AWSAppLoadBalancer `graphql:"... on AWSAppLoadBalancer"`
+	GcpLoadBalancer GCPLoadBalancer    `graphql:"... on GCPLoadBalancer"`
 }
 
 func (g *Ingress) GetDocs() map[string]string {
 	return map[string]string{
-		"k8s_ingress": "Kubernetes Ingress. Set if the gateway is provisioned on a Kubernetes cluster.",
-		"aws_alb":     "AWS Application Load Balancer. Set if the gateway is provisioned on AWS.",
+		"k8s_ingress":       "Kubernetes Ingress. Set if the gateway is provisioned on a Kubernetes cluster.",
+		"aws_alb":           "AWS Application Load Balancer. Set if the gateway is provisioned on AWS.",
+		"gcp_load_balancer": "GCP external Application Load Balancer. Set if the gateway is provisioned on Cloud Run.",
 	}
 }
 
 type K8sIngress struct {
-	K8sData `graphql:"data"`
+	K8sData       `graphql:"data"`
+	StaticIp      string
+	CertificateId string
+}
+
+func (a *K8sIngress) GetDocs() map[string]string {
+	return map[string]string{
+		"static_ip":      "The static IP address of the ingress. Set if the ingress is provisioned on GKE",
+		"certificate_id": "The TLS certificate of the ingress: the ARN of an ACM certificate on AWS, or the [id](https://cloud.google.com/apis/design/resource_names#relative_resource_name) of an SSL certificate in the form of `projects/{project}/global/sslCertificates/{certificate}` on GCP",
+	}
 }
 
 type AWSAppLoadBalancer struct {
-	Arn       string
-	Listeners []AWSAppLoadBalancerListener
+	Arn                   string
+	DnsName               string
+	CanonicalHostedZoneId string `tf:"zone_id"`
+	CertificateArn        string
+	Scheme                string
+	SecurityGroups        []AWSSecurityGroup
+	Subnets               []AWSSubnet
+	TargetGroups          []AWSTargetGroup
+	AccessLogBucket       string
+	Listeners             []AWSAppLoadBalancerListener
 }
 
 func (a *AWSAppLoadBalancer) GetDocs() map[string]string {
 	return map[string]string{
-		"arn":       "[ARN](https://docs.aws.amazon.com/general/latest/gr/aws-arns-and-namespaces.html) of the AWS Application Load Balancer.",
-		"listeners": "Listeners of the AWS Application Load Balancer.",
+		"arn":               "[ARN](https://docs.aws.amazon.com/general/latest/gr/aws-arns-and-namespaces.html) of the AWS Application Load Balancer.",
+		"dns_name":          "DNS name of the AWS Application Load Balancer.",
+		"zone_id":           "Canonical hosted zone ID of the AWS Application Load Balancer, for Route 53 alias records.",
+		"certificate_arn":   "ARN of the default TLS certificate of the HTTPS listeners of the AWS Application Load Balancer.",
+		"scheme":            "Scheme of the AWS Application Load Balancer, either `internet-facing` or `internal`.",
+		"security_groups":   "Security groups of the AWS Application Load Balancer.",
+		"subnets":           "Subnets of the AWS Application Load Balancer.",
+		"target_groups":     "Target groups of the AWS Application Load Balancer, one per service.",
+		"access_log_bucket": "Name of the S3 bucket the access logs of the AWS Application Load Balancer are stored in. Empty if access logs are disabled.",
+		"listeners":         "Listeners of the AWS Application Load Balancer.",
+	}
+}
+
+type AWSTargetGroup struct {
+	Arn     string
+	Service string
+	Port    int
+}
+
+func (a *AWSTargetGroup) GetDocs() map[string]string {
+	return map[string]string{
+		"arn":     "[ARN](https://docs.aws.amazon.com/general/latest/gr/aws-arns-and-namespaces.html) of the target group.",
+		"service": "Name of the Encore service the target group routes to.",
+		"port":    "Port of the targets of the target group.",
 	}
 }
 
 type AWSAppLoadBalancerListener struct {
-	Arn      string
-	Port     int
-	Protocol string
+	Arn             string
+	Port            int
+	Protocol        string
+	CertificateArns []string
+	DefaultActions  []AWSListenerAction
 }
 
 func (a *AWSAppLoadBalancerListener) GetDocs() map[string]string {
 	return map[string]string{
-		"arn":      "[ARN](https://docs.aws.amazon.com/general/latest/gr/aws-arns-and-namespaces.html) of the listener.",
-		"port":     "Port of the listener.",
-		"protocol": "Protocol of the listener.",
+		"arn":              "[ARN](https://docs.aws.amazon.com/general/latest/gr/aws-arns-and-namespaces.html) of the listener.",
+		"port":             "Port of the listener.",
+		"protocol":         "Protocol of the listener.",
+		"certificate_arns": "ARNs of the TLS certificates of the listener, the default certificate first.",
+		"default_actions":  "Default actions of the listener, for requests that match none of its rules.",
+	}
+}
+
+type AWSListenerAction struct {
+	Type             string
+	Order            int
+	TargetGroupArn   string
+	RedirectProtocol string
+	RedirectPort     string
+	StatusCode       string
+}
+
+func (a *AWSListenerAction) GetDocs() map[string]string {
+	return map[string]string{
+		"type":              "Type of the action, e.g. `forward`, `redirect` or `fixed-response`.",
+		"order":             "Order of the action among the default actions of the listener.",
+		"target_group_arn":  "ARN of the target group requests are forwarded to. Set if the type is `forward`.",
+		"redirect_protocol": "Protocol requests are redirected to. Set if the type is `redirect`.",
+		"redirect_port":     "Port requests are redirected to. Set if the type is `redirect`.",
+		"status_code":       "HTTP status code of the redirect or fixed response, e.g. `HTTP_301`.",
+	}
+}
+
+type GCPLoadBalancer struct {
+	IpAddress       GCPAddress
+	ForwardingRules []GCPForwardingRule
+	UrlMap          GCPUrlMap
+	BackendService  GCPBackendService
+	ServerlessNeg   GCPServerlessNeg
+}
+
+func (a *GCPLoadBalancer) GetDocs() map[string]string {
+	return map[string]string{
+		"ip_address":       "The global IP address of the load balancer.",
+		"forwarding_rules": "The global forwarding rules of the load balancer, e.g. for HTTP and HTTPS.",
+		"url_map":          "The URL map of the load balancer.",
+		"backend_service":  "The backend service of the load balancer, to attach Cloud Armor security policies and IAP to.",
+		"serverless_neg":   "The serverless network endpoint group of the Cloud Run service behind the backend service.",
+	}
+}
+
+type GCPForwardingRule struct {
+	SelfLink  string `tf:"id"`
+	PortRange string
+}
+
+func (a *GCPForwardingRule) GetDocs() map[string]string {
+	return map[string]string{
+		"id":         "The [id](https://cloud.google.com/apis/design/resource_names#relative_resource_name) of the forwarding rule in the form of `projects/{project}/global/forwardingRules/{forwarding_rule}`.",
+		"port_range": "The port range of the forwarding rule, e.g. `443-443`.",
+	}
+}
+
+type GCPUrlMap struct {
+	SelfLink string `tf:"id"`
+}
+
+func (a *GCPUrlMap) GetDocs() map[string]string {
+	return map[string]string{
+		"id": "The [id](https://cloud.google.com/apis/design/resource_names#relative_resource_name) of the URL map in the form of `projects/{project}/global/urlMaps/{url_map}`.",
+	}
+}
+
+type GCPBackendService struct {
+	SelfLink string `tf:"id"`
+	Name     string
+}
+
+func (a *GCPBackendService) GetDocs() map[string]string {
+	return map[string]string{
+		"id":   "The [id](https://cloud.google.com/apis/design/resource_names#relative_resource_name) of the backend service in the form of `projects/{project}/global/backendServices/{backend_service}`.",
+		"name": "The name of the backend service.",
+	}
+}
+
+type GCPServerlessNeg struct {
+	SelfLink string `tf:"id"`
+}
+
+func (a *GCPServerlessNeg) GetDocs() map[string]string {
+	return map[string]string{
+		"id": "The [id](https://cloud.google.com/apis/design/resource_names#relative_resource_name) of the network endpoint group in the form of `projects/{project}/regions/{region}/networkEndpointGroups/{network_endpoint_group}`.",
 	}
 }
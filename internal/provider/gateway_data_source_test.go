@@ -6,10 +6,23 @@ import (
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
 )
 
+func testGatewayHostnames(env string) resource.TestCheckFunc {
+	return resource.ComposeAggregateTestCheckFunc(
+		resource.TestCheckResourceAttr("data.encore_gateway.gateway", "base_url", "https://staging-app-"+env+".encr.app"),
+		resource.TestCheckResourceAttr("data.encore_gateway.gateway", "hostnames.#", "2"),
+		resource.TestCheckResourceAttr("data.encore_gateway.gateway", "hostnames.0", "staging-app-"+env+".encr.app"),
+		resource.TestCheckResourceAttr("data.encore_gateway.gateway", "hostnames.1", "api."+env+".example.com"),
+	)
+}
+
 func testAWSFargateGateway() resource.TestCheckFunc {
 	return resource.ComposeAggregateTestCheckFunc(
 		testAWSFargateService("data.encore_gateway.gateway"),
+		testGatewayHostnames("fargate"),
 		resource.TestCheckResourceAttr("data.encore_gateway.gateway", "aws_alb.arn", "arn:aws:elasticloadbalancing:region:account:loadbalancer/app/app-env"),
+		resource.TestCheckResourceAttr("data.encore_gateway.gateway", "aws_alb.dns_name", "app-env-1234567890.us-east-1.elb.amazonaws.com"),
+		resource.TestCheckResourceAttr("data.encore_gateway.gateway", "aws_alb.zone_id", "Z35SXDOTRQ7X7K"),
+		resource.TestCheckResourceAttr("data.encore_gateway.gateway", "aws_alb.certificate_arn", "arn:aws:acm:region:account:certificate/app-env"),
 		resource.TestCheckResourceAttr("data.encore_gateway.gateway", "aws_alb.listeners.0.arn", "arn:aws:elasticloadbalancing:region:account:listener/app/app-env/l1"),
 		resource.TestCheckResourceAttr("data.encore_gateway.gateway", "aws_alb.listeners.0.port", "80"),
 		resource.TestCheckResourceAttr("data.encore_gateway.gateway", "aws_alb.listeners.0.protocol", "HTTP"),
@@ -19,20 +32,61 @@ func testAWSFargateGateway() resource.TestCheckFunc {
 		resource.TestCheckResourceAttr("data.encore_gateway.gateway", "aws_alb.listeners.2.arn", "arn:aws:elasticloadbalancing:region:account:listener/app/app-env/l3"),
 		resource.TestCheckResourceAttr("data.encore_gateway.gateway", "aws_alb.listeners.2.port", "54355"),
 		resource.TestCheckResourceAttr("data.encore_gateway.gateway", "aws_alb.listeners.2.protocol", "HTTPS"),
+		resource.TestCheckResourceAttr("data.encore_gateway.gateway", "aws_alb.scheme", "internet-facing"),
+		resource.TestCheckResourceAttr("data.encore_gateway.gateway", "aws_alb.security_groups.0.id", "sg-alb"),
+		resource.TestCheckResourceAttr("data.encore_gateway.gateway", "aws_alb.subnets.#", "2"),
+		resource.TestCheckResourceAttr("data.encore_gateway.gateway", "aws_alb.subnets.1.az", "us-east-1b"),
+		resource.TestCheckResourceAttr("data.encore_gateway.gateway", "aws_alb.target_groups.0.arn", "arn:aws:elasticloadbalancing:region:account:targetgroup/app-env-encore/tg1"),
+		resource.TestCheckResourceAttr("data.encore_gateway.gateway", "aws_alb.target_groups.0.service", "encore"),
+		resource.TestCheckResourceAttr("data.encore_gateway.gateway", "aws_alb.target_groups.0.port", "8080"),
+		resource.TestCheckResourceAttr("data.encore_gateway.gateway", "aws_alb.access_log_bucket", "app-env-alb-logs"),
+		resource.TestCheckResourceAttr("data.encore_gateway.gateway", "aws_alb.listeners.0.certificate_arns.#", "0"),
+		resource.TestCheckResourceAttr("data.encore_gateway.gateway", "aws_alb.listeners.0.default_actions.0.type", "redirect"),
+		resource.TestCheckResourceAttr("data.encore_gateway.gateway", "aws_alb.listeners.0.default_actions.0.redirect_protocol", "HTTPS"),
+		resource.TestCheckResourceAttr("data.encore_gateway.gateway", "aws_alb.listeners.0.default_actions.0.redirect_port", "443"),
+		resource.TestCheckResourceAttr("data.encore_gateway.gateway", "aws_alb.listeners.0.default_actions.0.status_code", "HTTP_301"),
+		resource.TestCheckResourceAttr("data.encore_gateway.gateway", "aws_alb.listeners.1.certificate_arns.#", "2"),
+		resource.TestCheckResourceAttr("data.encore_gateway.gateway", "aws_alb.listeners.1.certificate_arns.1", "arn:aws:acm:region:account:certificate/custom-domain"),
+		resource.TestCheckResourceAttr("data.encore_gateway.gateway", "aws_alb.listeners.1.default_actions.0.type", "forward"),
+		resource.TestCheckResourceAttr("data.encore_gateway.gateway", "aws_alb.listeners.1.default_actions.0.order", "1"),
+		resource.TestCheckResourceAttr("data.encore_gateway.gateway", "aws_alb.listeners.1.default_actions.0.target_group_arn", "arn:aws:elasticloadbalancing:region:account:targetgroup/app-env-encore/tg1"),
 	)
 }
 
 func testEKSGateway() resource.TestCheckFunc {
 	return resource.ComposeAggregateTestCheckFunc(
 		testEKSService("data.encore_gateway.gateway", "api-gateway"),
+		testGatewayHostnames("eks"),
 		resource.TestCheckResourceAttr("data.encore_gateway.gateway", "k8s_ingress.name", "res"),
+		resource.TestCheckResourceAttr("data.encore_gateway.gateway", "k8s_ingress.static_ip", ""),
+		resource.TestCheckResourceAttr("data.encore_gateway.gateway", "k8s_ingress.certificate_id", "arn:aws:acm:region:account:certificate/app-env"),
 	)
 }
 
 func testGKEGateway() resource.TestCheckFunc {
 	return resource.ComposeAggregateTestCheckFunc(
 		testGKEService("data.encore_gateway.gateway", "api-gateway"),
+		testGatewayHostnames("gke"),
 		resource.TestCheckResourceAttr("data.encore_gateway.gateway", "k8s_ingress.name", "res-16or00pus0nak4albtkg-encore-aws-gateway-com"),
+		resource.TestCheckResourceAttr("data.encore_gateway.gateway", "k8s_ingress.static_ip", "34.117.10.5"),
+		resource.TestCheckResourceAttr("data.encore_gateway.gateway", "k8s_ingress.certificate_id", "projects/app-env/global/sslCertificates/api-gateway"),
+	)
+}
+
+func testCloudRunGateway() resource.TestCheckFunc {
+	return resource.ComposeAggregateTestCheckFunc(
+		testGCPCloudRun("data.encore_gateway.gateway", "api-gateway"),
+		testGatewayHostnames("cloudrun"),
+		resource.TestCheckResourceAttr("data.encore_gateway.gateway", "gcp_load_balancer.ip_address.id", "projects/app-env/global/addresses/app-env-gateway"),
+		resource.TestCheckResourceAttr("data.encore_gateway.gateway", "gcp_load_balancer.ip_address.address", "34.149.20.30"),
+		resource.TestCheckResourceAttr("data.encore_gateway.gateway", "gcp_load_balancer.forwarding_rules.#", "2"),
+		resource.TestCheckResourceAttr("data.encore_gateway.gateway", "gcp_load_balancer.forwarding_rules.1.id", "projects/app-env/global/forwardingRules/app-env-gateway-https"),
+		resource.TestCheckResourceAttr("data.encore_gateway.gateway", "gcp_load_balancer.forwarding_rules.1.port_range", "443-443"),
+		resource.TestCheckResourceAttr("data.encore_gateway.gateway", "gcp_load_balancer.url_map.id", "projects/app-env/global/urlMaps/app-env-gateway"),
+		resource.TestCheckResourceAttr("data.encore_gateway.gateway", "gcp_load_balancer.backend_service.id", "projects/app-env/global/backendServices/app-env-gateway"),
+		resource.TestCheckResourceAttr("data.encore_gateway.gateway", "gcp_load_balancer.backend_service.name", "app-env-gateway"),
+		resource.TestCheckResourceAttr("data.encore_gateway.gateway", "gcp_load_balancer.serverless_neg.id", "projects/app-env/regions/northamerica-northeast1/networkEndpointGroups/app-env-gateway"),
+		resource.TestCheckResourceAttr("data.encore_gateway.gateway", "aws_alb.arn", ""),
 	)
 }
 
@@ -54,7 +108,7 @@ func TestGatewayDataSource(t *testing.T) {
 			testStepForEnv(
 				"cloudrun",
 				testGatewayDataSourceConfig,
-				testGCPCloudRun("data.encore_gateway.gateway", "api-gateway"),
+				testCloudRunGateway(),
 			),
 			testStepForEnv(
 				"gke",
@@ -0,0 +1,60 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"iter"
+
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/list"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// maxListPageSize is the largest page size requested from the platform
+// when listing resources.
+var maxListPageSize = 100
+
+// listPageSize returns the page size to request for req, which
+// expects at most req.Limit results.
+func listPageSize(req list.ListRequest) int {
+	if req.Limit > 0 && req.Limit < int64(maxListPageSize) {
+		return int(req.Limit)
+	}
+	return maxListPageSize
+}
+
+// listResults converts the items of a paginated platform endpoint into
+// list results, using set to populate each result. It stops after
+// req.Limit results, or at the first error, which is reported as a
+// result with error diagnostics.
+func listResults[T any](ctx context.Context, req list.ListRequest, items iter.Seq2[T, error], kind string, set func(T, *list.ListResult)) iter.Seq[list.ListResult] {
+	return func(push func(list.ListResult) bool) {
+		var n int64
+		for item, err := range items {
+			result := req.NewListResult(ctx)
+			if err != nil {
+				result.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to list %s, got error: %s", kind, err))
+				push(result)
+				return
+			}
+			set(item, &result)
+			if !push(result) {
+				return
+			}
+			if n++; req.Limit > 0 && n >= req.Limit {
+				return
+			}
+		}
+	}
+}
+
+// nullTimeouts returns a null timeouts value with the given operations,
+// for resources that are populated outside of a plan.
+func nullTimeouts(operations ...string) timeouts.Value {
+	attrTypes := map[string]attr.Type{}
+	for _, op := range operations {
+		attrTypes[op] = types.StringType
+	}
+	return timeouts.Value{Object: types.ObjectNull(attrTypes)}
+}
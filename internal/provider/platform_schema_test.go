@@ -0,0 +1,212 @@
+package provider
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+
+	gqlschema "github.com/graph-gophers/graphql-go"
+	"github.com/graph-gophers/graphql-go/types"
+	"github.com/hasura/go-graphql-client"
+)
+
+// testPlatformSchema is the checked-in schema of the platform.
+var testPlatformSchema = sync.OnceValues(func() (*gqlschema.Schema, error) {
+	sdl, err := os.ReadFile(filepath.Join("testdata", "platform.graphql"))
+	if err != nil {
+		return nil, err
+	}
+	return gqlschema.ParseSchema(string(sdl), nil)
+})
+
+func loadPlatformSchema(t *testing.T) *gqlschema.Schema {
+	t.Helper()
+	schema, err := testPlatformSchema()
+	if err != nil {
+		t.Fatalf("parse testdata/platform.graphql: %v", err)
+	}
+	return schema
+}
+
+// validateQuery returns the errors of the query for v against schema.
+func validateQuery(schema *gqlschema.Schema, v interface{}, vars map[string]interface{}) ([]string, error) {
+	query, err := graphql.ConstructQuery(v, vars, graphql.OperationName(needsOperation))
+	if err != nil {
+		return nil, err
+	}
+	var errs []string
+	for _, e := range schema.ValidateWithVariables(query, vars) {
+		errs = append(errs, e.Error())
+	}
+	return errs, nil
+}
+
+// validateValue returns the errors of the response value v of type typ.
+// Values of unions must name their member with a __typename.
+func validateValue(path string, v interface{}, typ types.Type) []string {
+	if nonNull, ok := typ.(*types.NonNull); ok {
+		if v == nil {
+			return []string{fmt.Sprintf("%s: null for non-null type %s", path, typ)}
+		}
+		typ = nonNull.OfType
+	}
+	if v == nil {
+		return nil
+	}
+	switch typ := typ.(type) {
+	case *types.List:
+		list, ok := v.([]interface{})
+		if !ok {
+			return []string{fmt.Sprintf("%s: got %T for list type %s", path, v, typ)}
+		}
+		var errs []string
+		for i, elem := range list {
+			errs = append(errs, validateValue(fmt.Sprintf("%s.%d", path, i), elem, typ.OfType)...)
+		}
+		return errs
+	case *types.ScalarTypeDefinition:
+		var ok bool
+		switch typ.Name {
+		case "Int":
+			f, isNum := v.(float64)
+			ok = isNum && f == float64(int64(f))
+		case "Float":
+			_, ok = v.(float64)
+		case "Boolean":
+			_, ok = v.(bool)
+		default:
+			_, ok = v.(string)
+		}
+		if !ok {
+			return []string{fmt.Sprintf("%s: got %T for scalar %s", path, v, typ.Name)}
+		}
+		return nil
+	case *types.ObjectTypeDefinition:
+		obj, ok := v.(map[string]interface{})
+		if !ok {
+			return []string{fmt.Sprintf("%s: got %T for object type %s", path, v, typ.Name)}
+		}
+		var errs []string
+		for _, name := range slices.Sorted(maps.Keys(obj)) {
+			if name == "__typename" {
+				if obj[name] != typ.Name {
+					errs = append(errs, fmt.Sprintf("%s: got __typename %v for type %s", path, obj[name], typ.Name))
+				}
+				continue
+			}
+			field := typ.Fields.Get(name)
+			if field == nil {
+				errs = append(errs, fmt.Sprintf("%s: unknown field %q on type %s", path, name, typ.Name))
+				continue
+			}
+			errs = append(errs, validateValue(path+"."+name, obj[name], field.Type)...)
+		}
+		return errs
+	case *types.Union:
+		obj, ok := v.(map[string]interface{})
+		if !ok {
+			return []string{fmt.Sprintf("%s: got %T for union %s", path, v, typ.Name)}
+		}
+		typename, ok := obj["__typename"]
+		if !ok {
+			return []string{fmt.Sprintf("%s: no __typename for union %s", path, typ.Name)}
+		}
+		for _, member := range typ.UnionMemberTypes {
+			if typename == member.Name {
+				return validateValue(path, v, member)
+			}
+		}
+		return []string{fmt.Sprintf("%s: got __typename %v for union %s", path, typename, typ.Name)}
+	default:
+		return []string{fmt.Sprintf("%s: unsupported type %s", path, typ)}
+	}
+}
+
+// testFixtureEnvs are the environments of the fixtures in testdata, which
+// hold the needs query responses of the fake platform. Other files in
+// testdata are not responses of the platform.
+var testFixtureEnvs = []string{"cloudrun", "eks", "fargate", "gke"}
+
+func TestPlatformSchema(t *testing.T) {
+	schema := loadPlatformSchema(t)
+	vars := map[string]interface{}{
+		"appSlug": "app",
+		"envName": "env",
+		"types":   []TypeRef{"need.Topic"},
+	}
+
+	t.Run("needs query", func(t *testing.T) {
+		errs, err := validateQuery(schema, &needsQuery{}, vars)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, e := range errs {
+			t.Errorf("needs query: %s", e)
+		}
+	})
+
+	t.Run("introspection query", func(t *testing.T) {
+		query, err := graphql.ConstructQuery(&introspectionQuery{}, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, e := range schema.Validate(query) {
+			t.Errorf("introspection query: %s", e)
+		}
+	})
+
+	t.Run("reports errors", func(t *testing.T) {
+		var q struct {
+			App struct {
+				Env struct {
+					Needs []struct {
+						Satisfier struct {
+							Topic struct {
+								Arn string
+							} `graphql:"... on AWSSNSTopik"`
+							Sub struct {
+								ARN string `graphql:"amazonResourceName"`
+							} `graphql:"... on AWSSNSSubscription"`
+						}
+					} `graphql:"needs(sel:{typeRefs:$types})"`
+				} `graphql:"env(name: $envName)"`
+			} `graphql:"app(slug: $appSlug)"`
+		}
+		errs, err := validateQuery(schema, &q, vars)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := strings.Join(errs, "\n")
+		for _, want := range []string{`Unknown type "AWSSNSTopik"`, `Cannot query field "amazonResourceName" on type "AWSSNSSubscription"`} {
+			if !strings.Contains(got, want) {
+				t.Errorf("got errors %q, want %q", got, want)
+			}
+		}
+	})
+
+	query := schema.ASTSchema().EntryPoints["query"]
+	for _, env := range testFixtureEnvs {
+		fn := filepath.Join("testdata", env+".json")
+		t.Run(fn, func(t *testing.T) {
+			data, err := os.ReadFile(fn)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var resp struct {
+				Data map[string]interface{}
+			}
+			if err := json.Unmarshal(data, &resp); err != nil {
+				t.Fatal(err)
+			}
+			for _, e := range validateValue("data", resp.Data, query) {
+				t.Error(e)
+			}
+		})
+	}
+}
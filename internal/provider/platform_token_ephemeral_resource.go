@@ -0,0 +1,96 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var _ ephemeral.EphemeralResourceWithConfigure = &PlatformTokenEphemeralResource{}
+
+func NewPlatformToken() ephemeral.EphemeralResource {
+	return &PlatformTokenEphemeralResource{}
+}
+
+type PlatformTokenEphemeralResource struct {
+	client PlatformClient
+}
+
+// PlatformTokenEphemeralResourceModel describes the ephemeral resource data model.
+type PlatformTokenEphemeralResourceModel struct {
+	AccessToken types.String `tfsdk:"access_token"`
+	TokenType   types.String `tfsdk:"token_type"`
+	ExpiresAt   types.String `tfsdk:"expires_at"`
+	AppSlug     types.String `tfsdk:"app_slug"`
+}
+
+func (r *PlatformTokenEphemeralResource) Metadata(ctx context.Context, req ephemeral.MetadataRequest, resp *ephemeral.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_platform_token"
+}
+
+func (r *PlatformTokenEphemeralResource) Schema(ctx context.Context, req ephemeral.SchemaRequest, resp *ephemeral.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "A short-lived access token for the Encore Platform API, obtained by exchanging the provider auth key. " +
+			"Use it to authenticate other providers or scripts against the API instead of sharing the auth key.",
+		Attributes: map[string]schema.Attribute{
+			"access_token": schema.StringAttribute{
+				MarkdownDescription: "The access token. Send it in an `Authorization` header using the `token_type` scheme",
+				Computed:            true,
+				Sensitive:           true,
+			},
+			"token_type": schema.StringAttribute{
+				MarkdownDescription: "The type of the access token, e.g. `Bearer`",
+				Computed:            true,
+			},
+			"expires_at": schema.StringAttribute{
+				MarkdownDescription: "The time the access token expires, in RFC 3339 format",
+				Computed:            true,
+			},
+			"app_slug": schema.StringAttribute{
+				MarkdownDescription: "The slug of the app the token is authorized for. Null for tokens of users, which are authorized for all apps of the user, even if the provider `app` is set",
+				Computed:            true,
+			},
+		},
+	}
+}
+
+func (r *PlatformTokenEphemeralResource) Configure(ctx context.Context, req ephemeral.ConfigureRequest, resp *ephemeral.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	needs, ok := req.ProviderData.(*NeedsData)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Ephemeral Resource Configure Type",
+			fmt.Sprintf("Expected *NeedsData, received %T", req.ProviderData),
+		)
+		return
+	}
+
+	r.client = needs.client
+}
+
+func (r *PlatformTokenEphemeralResource) Open(ctx context.Context, req ephemeral.OpenRequest, resp *ephemeral.OpenResponse) {
+	token, err := r.client.Token(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to get platform token, got error: %s", err))
+		return
+	}
+
+	data := PlatformTokenEphemeralResourceModel{
+		AccessToken: types.StringValue(token.AccessToken),
+		TokenType:   types.StringValue(token.Type()),
+		ExpiresAt:   timeValue(&token.Expiry),
+	}
+	if appSlug := credentialsAppSlug(r.client); appSlug != "" {
+		data.AppSlug = types.StringValue(appSlug)
+	} else {
+		data.AppSlug = types.StringNull()
+	}
+	resp.Diagnostics.Append(resp.Result.Set(ctx, &data)...)
+}
@@ -0,0 +1,104 @@
+package provider
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-testing/echoprovider"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"github.com/hashicorp/terraform-plugin-testing/tfversion"
+)
+
+// testIssuedToken checks that name holds the access token that platform
+// issued last, along with its expiry.
+func testIssuedToken(platform *fakePlatform, name string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		access, expiry := platform.lastIssued()
+		if expiry.IsZero() {
+			return fmt.Errorf("no token issued")
+		}
+		return resource.ComposeAggregateTestCheckFunc(
+			resource.TestCheckResourceAttr(name, "data.access_token", access),
+			resource.TestCheckResourceAttr(name, "data.expires_at", expiry.Format(time.RFC3339)),
+		)(s)
+	}
+}
+
+func TestPlatformTokenEphemeralResource(t *testing.T) {
+	// A platform of its own, so that the token issued last is the one
+	// of this test.
+	platform := newFakePlatform(newTestPlatformState())
+	defer platform.Close()
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: map[string]func() (tfprotov6.ProviderServer, error){
+			"encore": platform.factories()["encore"],
+			"echo":   echoprovider.NewProviderServer(),
+		},
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_10_0),
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: testPlatformTokenConfig,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					testIssuedToken(platform, "echo.token"),
+					resource.TestCheckResourceAttr("echo.token", "data.token_type", "Bearer"),
+					resource.TestCheckResourceAttr("echo.token", "data.app_slug", "test"),
+				),
+			},
+		},
+	})
+}
+
+func TestPlatformTokenEphemeralResourceUser(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: map[string]func() (tfprotov6.ProviderServer, error){
+			"encore": testV6ProviderFactories["encore"],
+			"echo":   echoprovider.NewProviderServer(),
+		},
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_10_0),
+		},
+		Steps: []resource.TestStep{
+			{
+				// The token of a user is not scoped to the provider app.
+				Config: `
+provider "encore" {
+	auth_key = "user-key"
+	app      = "test"
+}
+
+ephemeral "encore_platform_token" "token" {}
+
+provider "echo" {
+	data = ephemeral.encore_platform_token.token
+}
+
+resource "echo" "token" {}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrSet("echo.token", "data.access_token"),
+					resource.TestCheckNoResourceAttr("echo.token", "data.app_slug"),
+				),
+			},
+		},
+	})
+}
+
+const testPlatformTokenConfig = `
+provider "encore" {
+	auth_key = "test"
+}
+
+ephemeral "encore_platform_token" "token" {}
+
+provider "echo" {
+	data = ephemeral.encore_platform_token.token
+}
+
+resource "echo" "token" {}
+`
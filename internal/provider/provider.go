@@ -4,16 +4,25 @@ import (
 	"context"
 	"os"
 
+	"github.com/hashicorp/terraform-plugin-framework/action"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
-	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
+	"github.com/hashicorp/terraform-plugin-framework/list"
 	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
 
 // Ensure EncoreProvider satisfies various provider interfaces.
-var _ provider.Provider = &EncoreProvider{}
+var (
+	_ provider.Provider                       = &EncoreProvider{}
+	_ provider.ProviderWithActions            = &EncoreProvider{}
+	_ provider.ProviderWithListResources      = &EncoreProvider{}
+	_ provider.ProviderWithEphemeralResources = &EncoreProvider{}
+)
 
 // EncoreProvider defines the provider implementation.
 type EncoreProvider struct {
@@ -22,13 +31,28 @@ type EncoreProvider struct {
 	// testing.
 	version string
 
-	clientFactory func(version string) PlatformClient
+	clientFactory func(version string, opts ClientOptions) PlatformClient
 }
 
 // EncoreProviderModel describes the provider data model.
 type EncoreProviderModel struct {
-	APIKey  types.String `tfsdk:"auth_key"`
-	EnvName types.String `tfsdk:"env"`
+	APIKey         types.String `tfsdk:"auth_key"`
+	APIKeyFile     types.String `tfsdk:"auth_key_file"`
+	OIDCToken      types.String `tfsdk:"oidc_token"`
+	OIDCTokenFile  types.String `tfsdk:"oidc_token_file"`
+	OIDCAudience   types.String `tfsdk:"oidc_audience"`
+	TokenCacheDir  types.String `tfsdk:"token_cache_dir"`
+	APIURL         types.String `tfsdk:"api_url"`
+	CACertPEM      types.String `tfsdk:"ca_cert_pem"`
+	CACertFile     types.String `tfsdk:"ca_cert_file"`
+	ProxyURL       types.String `tfsdk:"proxy_url"`
+	ClientCertPEM  types.String `tfsdk:"client_cert_pem"`
+	ClientKeyPEM   types.String `tfsdk:"client_key_pem"`
+	ClientCertFile types.String `tfsdk:"client_cert_file"`
+	ClientKeyFile  types.String `tfsdk:"client_key_file"`
+	EnvName        types.String `tfsdk:"env"`
+	App            types.String `tfsdk:"app"`
+	SnapshotFile   types.String `tfsdk:"snapshot_file"`
 }
 
 func (p *EncoreProvider) Metadata(ctx context.Context, req provider.MetadataRequest, resp *provider.MetadataResponse) {
@@ -43,8 +67,88 @@ func (p *EncoreProvider) Schema(ctx context.Context, req provider.SchemaRequest,
 				MarkdownDescription: "The default Encore environment to operate on, if not overridden on a resource. Defaults to primary environment.",
 				Optional:            true,
 			},
+			"app": schema.StringAttribute{
+				MarkdownDescription: "The slug of the Encore app to operate on, if not overridden on a data source. " +
+					"Defaults to the app the credentials are scoped to, and is required when authenticating as a user.",
+				Optional: true,
+			},
+			"snapshot_file": schema.StringAttribute{
+				MarkdownDescription: "Read data sources from a snapshot written by the `encore_snapshot` action instead of the Encore Platform, " +
+					"e.g. to plan without network access. Either a snapshot file of the provider `env`, " +
+					"or a directory of snapshots named `<env>.json`, optionally in a subdirectory per app. " +
+					"No credentials are needed, and resources, actions and ephemeral resources are unavailable.",
+				Optional: true,
+			},
 			"auth_key": schema.StringAttribute{
-				MarkdownDescription: "The [Encore Auth Key](https://encore.dev/docs/develop/auth-keys) to use to authenticate with the Encore Platform. Defaults to `ENCORE_AUTH_KEY` env var.",
+				MarkdownDescription: "The [Encore Auth Key](https://encore.dev/docs/develop/auth-keys) to use to authenticate with the Encore Platform. " +
+					"Credentials are looked up in order from `auth_key`, `auth_key_file`, `oidc_token`, `oidc_token_file`, " +
+					"the `ENCORE_AUTH_KEY` and `TFC_WORKLOAD_IDENTITY_TOKEN` env vars, the GitHub Actions OIDC token " +
+					"and finally the login stored by the Encore CLI. " +
+					"If the credentials are only known after apply, e.g. an auth key created in the same run, " +
+					"data sources must `depends_on` the resources they come from, unless Terraform runs with `-allow-deferral`.",
+				Optional: true,
+			},
+			"auth_key_file": schema.StringAttribute{
+				MarkdownDescription: "The path to a file containing the Encore Auth Key, e.g. a mounted secret.",
+				Optional:            true,
+			},
+			"oidc_token": schema.StringAttribute{
+				MarkdownDescription: "An OIDC ID token from a workload identity provider trusted by the Encore app, " +
+					"which is exchanged for a platform token instead of using an auth key.",
+				Optional:  true,
+				Sensitive: true,
+			},
+			"oidc_token_file": schema.StringAttribute{
+				MarkdownDescription: "The path to a file containing an OIDC ID token, e.g. a projected service account token.",
+				Optional:            true,
+			},
+			"oidc_audience": schema.StringAttribute{
+				MarkdownDescription: "The audience of the OIDC ID tokens, which the Encore Platform validates them against. " +
+					"Also the audience to request ID tokens for when running in GitHub Actions. Defaults to `encore.dev`.",
+				Optional: true,
+			},
+			"token_cache_dir": schema.StringAttribute{
+				MarkdownDescription: "A directory to cache platform tokens obtained with an auth key in, so they are reused " +
+					"across Terraform runs until they expire. May also be set with the `ENCORE_TOKEN_CACHE_DIR` env var. " +
+					"Tokens are not cached if unset.",
+				Optional: true,
+			},
+			"api_url": schema.StringAttribute{
+				MarkdownDescription: "The URL of the Encore Platform API. May also be set with the `ENCORE_API_URL` env var. " +
+					"Defaults to `" + DefaultBaseURL + "`.",
+				Optional: true,
+			},
+			"ca_cert_pem": schema.StringAttribute{
+				MarkdownDescription: "PEM encoded CA certificates to trust in addition to the system roots when connecting to the API, " +
+					"e.g. for a TLS intercepting proxy. Conflicts with `ca_cert_file`.",
+				Optional: true,
+			},
+			"ca_cert_file": schema.StringAttribute{
+				MarkdownDescription: "The path to a file of PEM encoded CA certificates to trust. Conflicts with `ca_cert_pem`.",
+				Optional:            true,
+			},
+			"proxy_url": schema.StringAttribute{
+				MarkdownDescription: "The URL of the proxy to connect to the API through. " +
+					"Defaults to the proxy given by the `HTTPS_PROXY` and `NO_PROXY` env vars.",
+				Optional: true,
+			},
+			"client_cert_pem": schema.StringAttribute{
+				MarkdownDescription: "A PEM encoded client certificate to present when connecting to the API, for mutual TLS. " +
+					"Requires `client_key_pem`.",
+				Optional: true,
+			},
+			"client_key_pem": schema.StringAttribute{
+				MarkdownDescription: "The PEM encoded private key of `client_cert_pem`.",
+				Optional:            true,
+				Sensitive:           true,
+			},
+			"client_cert_file": schema.StringAttribute{
+				MarkdownDescription: "The path to a PEM encoded client certificate, for mutual TLS. " +
+					"Requires `client_key_file`. Conflicts with `client_cert_pem`.",
+				Optional: true,
+			},
+			"client_key_file": schema.StringAttribute{
+				MarkdownDescription: "The path to the PEM encoded private key of `client_cert_file`.",
 				Optional:            true,
 			},
 		},
@@ -59,30 +163,69 @@ func (p *EncoreProvider) Configure(ctx context.Context, req provider.ConfigureRe
 		return
 	}
 
-	client := p.clientFactory(p.version)
-
-	apiKey := data.APIKey.ValueString()
-	if apiKey == "" {
-		apiKey = os.Getenv("ENCORE_AUTH_KEY")
-	}
-
-	if apiKey == "" {
-		resp.Diagnostics.AddAttributeError(path.Root("auth_key"), "No valid credential sources found", "Failed to find auth key in configuration or environment variables")
-		return
-	}
-
-	err := client.Auth(ctx, apiKey)
-	if err != nil {
-		resp.Diagnostics.AddAttributeError(path.Root("auth_key"), "No valid credential sources found", "Failed to authenticate with the Encore Platform: "+err.Error())
-		return
+	var client PlatformClient
+	if !req.Config.Raw.IsFullyKnown() {
+		// The configuration refers to values that are only known after
+		// other resources have been applied, e.g. a newly created auth key.
+		if req.ClientCapabilities.DeferralAllowed {
+			resp.Deferred = &provider.Deferred{Reason: provider.DeferredReasonProviderConfigUnknown}
+			return
+		}
+		tflog.Debug(ctx, "provider configuration is unknown, postponing authentication until apply")
+		client = newUnknownConfigClient(data.App.ValueString())
+	} else if fn := data.SnapshotFile.ValueString(); fn != "" {
+		envName := data.EnvName.ValueString()
+		if envName == "" {
+			envName = primaryEnv
+		}
+		client = newSnapshotClient(fn, data.App.ValueString(), envName)
+	} else {
+		var diags diag.Diagnostics
+		client, diags = p.newClient(ctx, data)
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
 	}
 	needs := NewNeedsData(client, data.EnvName.ValueString(), p.DataSources(ctx))
 	resp.DataSourceData = needs
 	resp.ResourceData = needs
+	resp.ActionData = needs
+	resp.ListResourceData = needs
+	resp.EphemeralResourceData = needs
+}
+
+// newClient returns a platform client authenticated with the credentials
+// of the provider configuration.
+func (p *EncoreProvider) newClient(ctx context.Context, data EncoreProviderModel) (PlatformClient, diag.Diagnostics) {
+	httpClient, diags := newHTTPClient(data)
+	if diags.HasError() {
+		return nil, diags
+	}
+	opts := ClientOptions{
+		BaseURL:       data.APIURL.ValueString(),
+		HTTPClient:    httpClient,
+		TokenCacheDir: data.TokenCacheDir.ValueString(),
+	}
+	if opts.TokenCacheDir == "" {
+		opts.TokenCacheDir = os.Getenv("ENCORE_TOKEN_CACHE_DIR")
+	}
+	client := p.clientFactory(p.version, opts)
+	diags.Append(authenticate(ctx, client, credentialChain(data))...)
+	if diags.HasError() {
+		return nil, diags
+	}
+	if app := data.App.ValueString(); app != "" {
+		client = withAppSlug(client, app)
+	}
+	return client, diags
 }
 
 func (p *EncoreProvider) Resources(ctx context.Context) []func() resource.Resource {
-	return []func() resource.Resource{}
+	return []func() resource.Resource{
+		NewCustomDomain,
+		NewDeployment,
+	}
 }
 
 func (p *EncoreProvider) DataSources(ctx context.Context) []func() datasource.DataSource {
@@ -93,6 +236,31 @@ func (p *EncoreProvider) DataSources(ctx context.Context) []func() datasource.Da
 		NewCache,
 		NewService,
 		NewGateway,
+		NewDeploymentDataSource,
+		NewEgressIPs,
+	}
+}
+
+func (p *EncoreProvider) Actions(ctx context.Context) []func() action.Action {
+	return []func() action.Action{
+		NewDeployAction,
+		NewRollbackAction,
+		NewRestartServiceAction,
+		NewSnapshotAction,
+	}
+}
+
+func (p *EncoreProvider) EphemeralResources(ctx context.Context) []func() ephemeral.EphemeralResource {
+	return []func() ephemeral.EphemeralResource{
+		NewDatabaseCredentials,
+		NewPlatformToken,
+	}
+}
+
+func (p *EncoreProvider) ListResources(ctx context.Context) []func() list.ListResource {
+	return []func() list.ListResource{
+		NewCustomDomainList,
+		NewDeploymentList,
 	}
 }
 
@@ -7,59 +7,66 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"io"
 	"net/http"
-	"os"
+	"net/url"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
 	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
 	"github.com/hasura/go-graphql-client"
+	"golang.org/x/oauth2"
 )
 
-func newTestPlatformClient(string) PlatformClient {
-	tp := &TestPlatformClient{}
-	tp.gql = graphql.NewClient("http://localhost:8080/graphql", tp)
-	return tp
+func newTestPlatformClient(string, ClientOptions) PlatformClient {
+	return &TestPlatformClient{}
 }
 
-type TestPlatformClient struct {
-	gql *graphql.Client
-}
+// TestPlatformClient is a platform client that records logins in
+// testPlatform without making requests, for testing credential sources.
+// Acceptance tests use a PlatformClientImpl talking to a fakePlatform.
+type TestPlatformClient struct{}
 
-func (t TestPlatformClient) Do(req *http.Request) (*http.Response, error) {
-	body, err := io.ReadAll(req.Body)
-	if err != nil {
-		return nil, err
-	}
-	reqBody := struct {
-		Query     string                 `json:"query"`
-		Variables map[string]interface{} `json:"variables"`
-	}{}
-	if err := json.Unmarshal(body, &reqBody); err != nil {
-		return nil, err
-	}
-	fileStream, err := os.Open(fmt.Sprintf("testdata/%s.json", reqBody.Variables["envName"]))
-	if err != nil {
-		return nil, err
-	}
-	return &http.Response{
-		StatusCode: 200,
-		Body:       fileStream,
-	}, nil
+func (t TestPlatformClient) Auth(ctx context.Context, authKey string) error {
+	return testPlatform.login("auth_key:" + authKey)
+}
 
+func (t TestPlatformClient) AuthOIDC(ctx context.Context, idToken, audience string) error {
+	return testPlatform.login("oidc:" + idToken + "@" + audience)
 }
 
-func (t TestPlatformClient) Auth(ctx context.Context, authKey string) error {
-	return nil
+func (t TestPlatformClient) AuthToken(ctx context.Context, data *OAuthData) error {
+	return testPlatform.login("token:" + data.Token.AccessToken)
 }
 
 func (t TestPlatformClient) Call(ctx context.Context, method, path string, reqParams, respParams interface{}) error {
-	return nil
+	resp, err := testPlatform.handle(method, path, reqParams)
+	if err != nil || resp == nil || respParams == nil {
+		return err
+	}
+	return remarshal(resp, respParams)
+}
+
+func (t TestPlatformClient) Token(ctx context.Context) (*oauth2.Token, error) {
+	return &oauth2.Token{
+		AccessToken: "test-access-token",
+		TokenType:   "Bearer",
+		Expiry:      time.Date(2024, 1, 2, 4, 4, 5, 0, time.UTC),
+	}, nil
 }
 
 func (t TestPlatformClient) GQL() *graphql.Client {
-	return t.gql
+	return nil
 }
 
 func (t TestPlatformClient) AppSlug() string {
@@ -68,15 +75,302 @@ func (t TestPlatformClient) AppSlug() string {
 
 var _ PlatformClient = &TestPlatformClient{}
 
+func init() {
+	pollInterval = 10 * time.Millisecond
+	retryBackoff = time.Millisecond
+	// Make list resources fetch several pages.
+	maxListPageSize = 2
+}
+
+// testPlatform holds the state of the REST endpoints served by
+// testFakePlatform. It is shared by all clients, since Terraform
+// configures the provider anew for every command.
+var testPlatform = newTestPlatformState()
+
+func newTestPlatformState() *testPlatformState {
+	return &testPlatformState{
+		domains:  map[string]*CustomDomain{},
+		deploys:  map[string][]*Deployment{},
+		restarts: map[string][]*ServiceRestart{},
+		leases:   map[string]*testLease{},
+	}
+}
+
+type testPlatformState struct {
+	mu       sync.Mutex
+	domains  map[string]*CustomDomain     // keyed by env/hostname
+	deploys  map[string][]*Deployment     // keyed by env, oldest first
+	restarts map[string][]*ServiceRestart // keyed by env/service, oldest first
+	leases   map[string]*testLease        // keyed by env/database/lease id
+	logins   []string                     // the credentials used to log in, oldest first
+
+	needsQueries []string // the app/env of each needs query, oldest first
+}
+
+// login records a login with the given credentials, rejecting any
+// credentials containing "invalid".
+func (s *testPlatformState) login(creds string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if strings.Contains(creds, "invalid") {
+		return Error{HTTPStatus: "401 Unauthorized", HTTPCode: http.StatusUnauthorized, Code: "unauthenticated"}
+	}
+	s.logins = append(s.logins, creds)
+	return nil
+}
+
+// testLease is a database credentials lease handed out by the test platform.
+type testLease struct {
+	DatabaseCredentials
+	Renewals int
+	Revoked  bool
+}
+
+func testNotFound(path string) error {
+	return Error{
+		HTTPStatus: "404 Not Found",
+		HTTPCode:   http.StatusNotFound,
+		Code:       "not_found",
+		Detail:     json.RawMessage(strconv.Quote(path)),
+	}
+}
+
+// handle serves a platform API call of the form /apps/{app}/envs/{env}/{kind}/...
+func (s *testPlatformState) handle(method, path string, reqParams interface{}) (interface{}, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	path, rawQuery, _ := strings.Cut(path, "?")
+	query, _ := url.ParseQuery(rawQuery)
+	parts := strings.Split(strings.TrimPrefix(path, "/"), "/")
+	for i, p := range parts {
+		parts[i], _ = url.PathUnescape(p)
+	}
+	if len(parts) < 5 || parts[0] != "apps" || parts[2] != "envs" {
+		return nil, testNotFound(path)
+	}
+	env, kind, rest := parts[3], parts[4], parts[5:]
+	switch {
+	case kind == "domains" && len(rest) == 0 && method == "POST":
+		var params struct {
+			Hostname   string `json:"hostname"`
+			PathPrefix string `json:"path_prefix"`
+		}
+		if err := remarshal(reqParams, &params); err != nil {
+			return nil, err
+		}
+		// Path prefixes are normalized to start with a slash.
+		if params.PathPrefix != "" && !strings.HasPrefix(params.PathPrefix, "/") {
+			params.PathPrefix = "/" + params.PathPrefix
+		}
+		d := &CustomDomain{
+			Hostname:          params.Hostname,
+			PathPrefix:        params.PathPrefix,
+			CertificateStatus: CertificatePending,
+			ValidationRecords: []DNSRecord{{
+				Name:  "_acme-challenge." + params.Hostname,
+				Type:  "CNAME",
+				Value: params.Hostname + ".validation.encr.app",
+			}},
+			TargetRecords: []DNSRecord{{
+				Name:  params.Hostname,
+				Type:  "CNAME",
+				Value: env + ".gateway.encr.app",
+			}},
+		}
+		s.domains[env+"/"+d.Hostname] = d
+		return d, nil
+	case kind == "domains" && len(rest) == 0 && method == "GET":
+		var matches []*CustomDomain
+		for key, d := range s.domains {
+			if strings.HasPrefix(key, env+"/") {
+				matches = append(matches, d)
+			}
+		}
+		slices.SortFunc(matches, func(a, b *CustomDomain) int {
+			return strings.Compare(a.Hostname, b.Hostname)
+		})
+		return testPage(matches, query), nil
+	case kind == "domains" && len(rest) == 1:
+		d, ok := s.domains[env+"/"+rest[0]]
+		if !ok {
+			return nil, testNotFound(path)
+		}
+		switch method {
+		case "GET":
+			// Each poll of a pending certificate completes its issuance.
+			if d.CertificateStatus == CertificatePending {
+				d.CertificateStatus = CertificateIssued
+				if strings.HasPrefix(d.Hostname, "invalid.") {
+					d.CertificateStatus = CertificateFailed
+				}
+			}
+			return d, nil
+		case "DELETE":
+			delete(s.domains, env+"/"+rest[0])
+			return nil, nil
+		}
+	case kind == "deploys" && len(rest) == 0 && method == "POST":
+		var params struct {
+			Commit string `json:"commit"`
+			Branch string `json:"branch"`
+		}
+		if err := remarshal(reqParams, &params); err != nil {
+			return nil, err
+		}
+		if params.Commit == "" {
+			params.Commit = "head-of-" + params.Branch
+		} else if strings.HasPrefix(testFullCommit, params.Commit) {
+			params.Commit = testFullCommit
+		}
+		return s.newDeployment(env, params.Commit, params.Branch), nil
+	case kind == "deploys" && len(rest) == 0 && method == "GET":
+		var matches []*Deployment
+		for i := len(s.deploys[env]) - 1; i >= 0; i-- {
+			if d := s.deploys[env][i]; query.Get("status") == "" || d.Status == query.Get("status") {
+				matches = append(matches, d)
+			}
+		}
+		return testPage(matches, query), nil
+	case kind == "deploys" && len(rest) == 1 && method == "POST" && strings.HasSuffix(rest[0], ":rollback"):
+		for _, d := range s.deploys[env] {
+			if d.ID == strings.TrimSuffix(rest[0], ":rollback") {
+				return s.newDeployment(env, d.Commit, d.Branch), nil
+			}
+		}
+	case kind == "deploys" && len(rest) == 1 && method == "GET":
+		for _, d := range s.deploys[env] {
+			if d.ID != rest[0] {
+				continue
+			}
+			// Each poll advances the deployment, failing commits prefixed with "bad".
+			switch d.Status {
+			case DeploymentQueued:
+				d.Status = DeploymentRunning
+			case DeploymentRunning:
+				finished := d.CreatedAt.Add(time.Minute)
+				d.FinishedAt = &finished
+				d.Status = DeploymentSuccess
+				if strings.HasPrefix(d.Commit, "bad") {
+					d.Status = DeploymentFailure
+					d.Error = "service api failed health checks"
+				}
+			}
+			return d, nil
+		}
+	case kind == "databases" && len(rest) == 2 && rest[1] == "credentials" && method == "POST":
+		var params struct {
+			ReadOnly bool `json:"read_only"`
+		}
+		if err := remarshal(reqParams, &params); err != nil {
+			return nil, err
+		}
+		user := rest[0] + "_writer"
+		if params.ReadOnly {
+			user = rest[0] + "_reader"
+		}
+		l := &testLease{DatabaseCredentials: DatabaseCredentials{
+			LeaseID:  fmt.Sprintf("lease_%d", len(s.leases)+1),
+			Host:     rest[0] + "." + env + ".db.encr.app",
+			Port:     5432,
+			Database: rest[0],
+			User:     user,
+			Password: "hunter2",
+			// Leases expire right away, so that Terraform renews them.
+			ExpiresAt: time.Now().Add(renewBefore).UTC().Truncate(time.Second),
+		}}
+		s.leases[env+"/"+rest[0]+"/"+l.LeaseID] = l
+		return l.DatabaseCredentials, nil
+	case kind == "databases" && len(rest) == 3 && rest[1] == "credentials":
+		l, ok := s.leases[env+"/"+rest[0]+"/"+strings.TrimSuffix(rest[2], ":renew")]
+		if !ok || l.Revoked {
+			return nil, testNotFound(path)
+		}
+		switch {
+		case method == "POST" && strings.HasSuffix(rest[2], ":renew"):
+			l.Renewals++
+			l.ExpiresAt = time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+			return l.DatabaseCredentials, nil
+		case method == "DELETE":
+			l.Revoked = true
+			return nil, nil
+		}
+	case kind == "services" && len(rest) == 1 && method == "POST" && strings.HasSuffix(rest[0], ":restart"):
+		svc := strings.TrimSuffix(rest[0], ":restart")
+		r := &ServiceRestart{
+			ID:     fmt.Sprintf("restart_%s_%d", svc, len(s.restarts[env+"/"+svc])+1),
+			Status: DeploymentRunning,
+		}
+		s.restarts[env+"/"+svc] = append(s.restarts[env+"/"+svc], r)
+		return r, nil
+	case kind == "services" && len(rest) == 3 && rest[1] == "restarts" && method == "GET":
+		for _, r := range s.restarts[env+"/"+rest[0]] {
+			if r.ID != rest[2] {
+				continue
+			}
+			// Each poll completes the restart, failing services named "broken".
+			if r.Status == DeploymentRunning {
+				r.Status = DeploymentSuccess
+				if rest[0] == "broken" {
+					r.Status = DeploymentFailure
+					r.Error = "container exited with code 1"
+				}
+			}
+			return r, nil
+		}
+	}
+	return nil, testNotFound(path)
+}
+
+// testFullCommit is the full SHA that abbreviations of it are resolved to
+// when deployed.
+const testFullCommit = "c0ffee5a1f3b2d4e6f708192a3b4c5d6e7f80912"
+
+func (s *testPlatformState) newDeployment(env, commit, branch string) *Deployment {
+	d := &Deployment{
+		ID:        fmt.Sprintf("deploy_%s_%d", env, len(s.deploys[env])+1),
+		Commit:    commit,
+		Branch:    branch,
+		Status:    DeploymentQueued,
+		CreatedAt: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+	}
+	s.deploys[env] = append(s.deploys[env], d)
+	return d
+}
+
+// testPage returns the page of items selected by the limit and
+// page_token query parameters. Page tokens are offsets into items.
+func testPage[T any](items []T, query url.Values) Page[T] {
+	offset, _ := strconv.Atoi(query.Get("page_token"))
+	items = items[min(offset, len(items)):]
+	var page Page[T]
+	if limit, err := strconv.Atoi(query.Get("limit")); err == nil && len(items) > limit {
+		items = items[:limit]
+		page.NextPageToken = strconv.Itoa(offset + limit)
+	}
+	page.Items = items
+	return page
+}
+
+func remarshal(from, to interface{}) error {
+	data, err := json.Marshal(from)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, to)
+}
+
 // testV6ProviderFactories are used to instantiate a provider during
 // acceptance testing. The factory function will be invoked for every Terraform
 // CLI command executed to create a provider server to which the CLI can
 // reattach.
 var testV6ProviderFactories = map[string]func() (tfprotov6.ProviderServer, error){
-	"encore": providerserver.NewProtocol6WithError(newForTest("test", newTestPlatformClient)()),
+	"encore": providerserver.NewProtocol6WithError(newForTest("test", func(version string, opts ClientOptions) PlatformClient {
+		return testFakePlatform().newClient(version, opts)
+	})()),
 }
 
-func newForTest(version string, clientFactory func(string) PlatformClient) func() provider.Provider {
+func newForTest(version string, clientFactory func(string, ClientOptions) PlatformClient) func() provider.Provider {
 	return func() provider.Provider {
 		return &EncoreProvider{
 			version:       version,
@@ -84,3 +378,117 @@ func newForTest(version string, clientFactory func(string) PlatformClient) func(
 		}
 	}
 }
+
+// testUnknownConfig returns a provider configuration with an unknown auth_key.
+func testUnknownConfig(t *testing.T, p provider.Provider) tfsdk.Config {
+	ctx := context.Background()
+	var schemaResp provider.SchemaResponse
+	p.Schema(ctx, provider.SchemaRequest{}, &schemaResp)
+	vals := map[string]tftypes.Value{}
+	for name, attr := range schemaResp.Schema.Attributes {
+		vals[name] = tftypes.NewValue(attr.GetType().TerraformType(ctx), nil)
+	}
+	vals["auth_key"] = tftypes.NewValue(tftypes.String, tftypes.UnknownValue)
+	return tfsdk.Config{
+		Raw:    tftypes.NewValue(schemaResp.Schema.Type().TerraformType(ctx), vals),
+		Schema: schemaResp.Schema,
+	}
+}
+
+func TestProviderConfigureUnknown(t *testing.T) {
+	ctx := context.Background()
+	p := newForTest("test", func(string, ClientOptions) PlatformClient {
+		t.Fatal("unexpected client for unknown configuration")
+		return nil
+	})()
+
+	var resp provider.ConfigureResponse
+	p.Configure(ctx, provider.ConfigureRequest{
+		Config:             testUnknownConfig(t, p),
+		ClientCapabilities: provider.ConfigureProviderClientCapabilities{DeferralAllowed: true},
+	}, &resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("got diagnostics %v", resp.Diagnostics)
+	} else if resp.Deferred == nil || resp.Deferred.Reason != provider.DeferredReasonProviderConfigUnknown {
+		t.Errorf("got deferred %v, want provider config unknown", resp.Deferred)
+	}
+
+	resp = provider.ConfigureResponse{}
+	p.Configure(ctx, provider.ConfigureRequest{Config: testUnknownConfig(t, p)}, &resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("got diagnostics %v", resp.Diagnostics)
+	} else if resp.Deferred != nil {
+		t.Errorf("got deferred %v without deferral support", resp.Deferred)
+	}
+	needs, ok := resp.DataSourceData.(*NeedsData)
+	if !ok {
+		t.Fatalf("got data source data %T", resp.DataSourceData)
+	}
+	if err := needs.client.Call(ctx, "GET", "/", nil, nil); err != errConfigUnknown {
+		t.Errorf("got error %v, want %v", err, errConfigUnknown)
+	}
+}
+
+func TestProviderUnknownAuthKey(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				// The auth key is unknown when planning the custom domain.
+				Config: fmt.Sprintf(testProviderUnknownAuthKeyConfig, "lazy-key-1"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("encore_custom_domain.domain", "target_records.0.value", "lazy.gateway.encr.app"),
+					func(*terraform.State) error {
+						testPlatform.mu.Lock()
+						defer testPlatform.mu.Unlock()
+						if !slices.Contains(testPlatform.logins, "auth_key:lazy-key-1") {
+							return fmt.Errorf("got logins %v, want auth_key:lazy-key-1", testPlatform.logins)
+						}
+						return nil
+					},
+				),
+			},
+			{
+				// Refreshing the custom domain keeps its state until the new auth key is known.
+				Config: fmt.Sprintf(testProviderUnknownAuthKeyConfig, "lazy-key-2"),
+				Check:  resource.TestCheckResourceAttr("encore_custom_domain.domain", "target_records.0.value", "lazy.gateway.encr.app"),
+			},
+			{
+				// Data sources that depend on the auth key are read during apply.
+				PreConfig: func() {
+					testPlatform.mu.Lock()
+					defer testPlatform.mu.Unlock()
+					testPlatform.newDeployment("lazy", "abc123", "").Status = DeploymentSuccess
+				},
+				Config: fmt.Sprintf(testProviderUnknownAuthKeyConfig, "lazy-key-3") + `
+data "encore_deployment" "latest" {
+	depends_on = [terraform_data.auth_key]
+}
+`,
+				Check: resource.TestCheckResourceAttr("data.encore_deployment.latest", "commit", "abc123"),
+			},
+			{
+				// Other data sources cannot be read until the auth key is known.
+				Config: fmt.Sprintf(testProviderUnknownAuthKeyConfig, "lazy-key-4") + `
+data "encore_deployment" "latest" {}
+`,
+				ExpectError: regexp.MustCompile(`(?s)data\.encore_deployment\.latest.*not\s+known\s+until\s+apply`),
+			},
+		},
+	})
+}
+
+const testProviderUnknownAuthKeyConfig = `
+resource "terraform_data" "auth_key" {
+	input = %q
+}
+
+provider "encore" {
+	auth_key = terraform_data.auth_key.output
+	env      = "lazy"
+}
+
+resource "encore_custom_domain" "domain" {
+	hostname = "lazy.example.com"
+}
+`
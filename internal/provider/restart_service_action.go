@@ -0,0 +1,114 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/action"
+	"github.com/hashicorp/terraform-plugin-framework/action/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var (
+	_ action.ActionWithConfigure      = &RestartServiceAction{}
+	_ action.ActionWithValidateConfig = &RestartServiceAction{}
+)
+
+func NewRestartServiceAction() action.Action {
+	return &RestartServiceAction{}
+}
+
+// ServiceRestart is a rolling restart of a service as returned by the Encore Platform.
+// It uses the same statuses as a Deployment.
+type ServiceRestart struct {
+	ID     string `json:"id"`
+	Status string `json:"status"`
+	Error  string `json:"error"`
+}
+
+type RestartServiceAction struct {
+	platformAction
+}
+
+// RestartServiceActionModel describes the action data model.
+type RestartServiceActionModel struct {
+	Env     types.String `tfsdk:"env"`
+	Service types.String `tfsdk:"service"`
+	Timeout types.String `tfsdk:"timeout"`
+}
+
+func (a *RestartServiceAction) Metadata(ctx context.Context, req action.MetadataRequest, resp *action.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_restart_service"
+}
+
+func (a *RestartServiceAction) Schema(ctx context.Context, req action.SchemaRequest, resp *action.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Performs a rolling restart of an Encore service, e.g. to pick up changed cloud permissions.",
+		Attributes: map[string]schema.Attribute{
+			"env": schema.StringAttribute{
+				MarkdownDescription: "The environment of the service. Defaults to the provider environment",
+				Optional:            true,
+			},
+			"service": schema.StringAttribute{
+				MarkdownDescription: "The name of the Encore service to restart",
+				Required:            true,
+			},
+			"timeout": timeoutAttribute("restart"),
+		},
+	}
+}
+
+func (a *RestartServiceAction) ValidateConfig(ctx context.Context, req action.ValidateConfigRequest, resp *action.ValidateConfigResponse) {
+	var data RestartServiceActionModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	_, diags := actionTimeout(data.Timeout)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (a *RestartServiceAction) Invoke(ctx context.Context, req action.InvokeRequest, resp *action.InvokeResponse) {
+	var data RestartServiceActionModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	resp.Diagnostics.Append(checkApp(a.client)...)
+	timeout, diags := actionTimeout(data.Timeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	envName, service := a.env(data.Env), data.Service.ValueString()
+	path := escapef("/apps/%s/envs/%s/services/%s", a.client.AppSlug(), envName, service)
+
+	var restart ServiceRestart
+	err := a.client.Call(ctx, "POST", path+":restart", nil, &restart)
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to restart service %s, got error: %s", service, err))
+		return
+	}
+	a.progress(ctx, resp, fmt.Sprintf("Restart of service %s in %s is %s", service, envName, restart.Status))
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	status := restart.Status
+	err = waitFor(ctx, func(ctx context.Context) (bool, error) {
+		err := a.client.Call(ctx, "GET", path+escapef("/restarts/%s", restart.ID), nil, &restart)
+		if err != nil {
+			return false, err
+		}
+		if restart.Status != status {
+			status = restart.Status
+			a.progress(ctx, resp, fmt.Sprintf("Restart of service %s in %s is %s", service, envName, restart.Status))
+		}
+		return isTerminalStatus(restart.Status), nil
+	})
+	if err != nil {
+		resp.Diagnostics.AddError("Restart Error", fmt.Sprintf("Unable to wait for restart of service %s to complete, got error: %s", service, err))
+	} else if restart.Status != DeploymentSuccess {
+		msg := fmt.Sprintf("Restart of service %s finished with status %q", service, restart.Status)
+		if restart.Error != "" {
+			msg += ": " + restart.Error
+		}
+		resp.Diagnostics.AddError("Restart Error", msg)
+	}
+}
@@ -0,0 +1,68 @@
+package provider
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"github.com/hashicorp/terraform-plugin-testing/tfversion"
+)
+
+func testServiceRestarts(key string, want ...string) resource.TestCheckFunc {
+	return func(*terraform.State) error {
+		testPlatform.mu.Lock()
+		defer testPlatform.mu.Unlock()
+		var got []string
+		for _, r := range testPlatform.restarts[key] {
+			got = append(got, r.Status)
+		}
+		if fmt.Sprint(got) != fmt.Sprint(want) {
+			return fmt.Errorf("got restarts %v, want %v", got, want)
+		}
+		return nil
+	}
+}
+
+func TestRestartServiceAction(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testV6ProviderFactories,
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_14_0),
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testRestartServiceActionConfig, "api"),
+				Check:  testServiceRestarts("restart-action/api", "success"),
+			},
+			{
+				Config:      fmt.Sprintf(testRestartServiceActionConfig, "broken"),
+				ExpectError: regexp.MustCompile(`container exited with code 1`),
+			},
+		},
+	})
+}
+
+const testRestartServiceActionConfig = `
+provider "encore" {
+	auth_key = "test"
+	env = "restart-action"
+}
+
+action "encore_restart_service" "restart" {
+	config {
+		service = "%[1]s"
+	}
+}
+
+resource "terraform_data" "trigger" {
+	input = "%[1]s"
+	lifecycle {
+		action_trigger {
+			events  = [after_create, after_update]
+			actions = [action.encore_restart_service.restart]
+		}
+	}
+}
+`
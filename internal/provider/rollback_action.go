@@ -0,0 +1,96 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/action"
+	"github.com/hashicorp/terraform-plugin-framework/action/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var (
+	_ action.ActionWithConfigure      = &RollbackAction{}
+	_ action.ActionWithValidateConfig = &RollbackAction{}
+)
+
+func NewRollbackAction() action.Action {
+	return &RollbackAction{}
+}
+
+type RollbackAction struct {
+	platformAction
+}
+
+// RollbackActionModel describes the action data model.
+type RollbackActionModel struct {
+	Env          types.String `tfsdk:"env"`
+	DeploymentID types.String `tfsdk:"deployment_id"`
+	Timeout      types.String `tfsdk:"timeout"`
+}
+
+func (a *RollbackAction) Metadata(ctx context.Context, req action.MetadataRequest, resp *action.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_rollback"
+}
+
+func (a *RollbackAction) Schema(ctx context.Context, req action.SchemaRequest, resp *action.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Rolls an Encore environment back to a previous deployment and waits for the rollback to complete.",
+		Attributes: map[string]schema.Attribute{
+			"env": schema.StringAttribute{
+				MarkdownDescription: "The environment to roll back. Defaults to the provider environment",
+				Optional:            true,
+			},
+			"deployment_id": schema.StringAttribute{
+				MarkdownDescription: "The id of the deployment to roll back to. Defaults to the successful deployment before the current one",
+				Optional:            true,
+			},
+			"timeout": timeoutAttribute("rollback"),
+		},
+	}
+}
+
+func (a *RollbackAction) ValidateConfig(ctx context.Context, req action.ValidateConfigRequest, resp *action.ValidateConfigResponse) {
+	var data RollbackActionModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	_, diags := actionTimeout(data.Timeout)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (a *RollbackAction) Invoke(ctx context.Context, req action.InvokeRequest, resp *action.InvokeResponse) {
+	var data RollbackActionModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	resp.Diagnostics.Append(checkApp(a.client)...)
+	timeout, diags := actionTimeout(data.Timeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	envName := a.env(data.Env)
+
+	target := data.DeploymentID.ValueString()
+	if target == "" {
+		var deploys Page[*Deployment]
+		err := a.client.Call(ctx, "GET", deploymentsPath(a.client.AppSlug(), envName)+"?status=success&limit=2", nil, &deploys)
+		if err != nil {
+			resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to list deployments, got error: %s", err))
+			return
+		} else if len(deploys.Items) < 2 {
+			resp.Diagnostics.AddError("No Deployment Found", fmt.Sprintf("Environment %s has no previous successful deployment to roll back to", envName))
+			return
+		}
+		target = deploys.Items[1].ID
+	}
+
+	a.progress(ctx, resp, fmt.Sprintf("Rolling back %s to deployment %s", envName, target))
+	var deploy Deployment
+	err := a.client.Call(ctx, "POST", deploymentsPath(a.client.AppSlug(), envName, target)+":rollback", nil, &deploy)
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to roll back to deployment %s, got error: %s", target, err))
+		return
+	}
+	resp.Diagnostics.Append(a.waitForDeployment(ctx, resp, envName, &deploy, timeout)...)
+}
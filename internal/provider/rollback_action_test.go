@@ -0,0 +1,63 @@
+package provider
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/tfversion"
+)
+
+func TestRollbackAction(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testV6ProviderFactories,
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_14_0),
+		},
+		Steps: []resource.TestStep{
+			{
+				Config:      fmt.Sprintf(testRollbackActionConfig, "v1", ""),
+				ExpectError: regexp.MustCompile(`no previous successful\s+deployment`),
+			},
+			{
+				PreConfig: func() {
+					testPlatform.mu.Lock()
+					defer testPlatform.mu.Unlock()
+					for _, commit := range []string{"aaa", "bbb", "ccc"} {
+						testPlatform.newDeployment("rollback-action", commit, "").Status = DeploymentSuccess
+					}
+				},
+				Config: fmt.Sprintf(testRollbackActionConfig, "v2", ""),
+				Check:  testDeployments("rollback-action", "aaa:success", "bbb:success", "ccc:success", "bbb:success"),
+			},
+			{
+				Config: fmt.Sprintf(testRollbackActionConfig, "v3", `deployment_id = "deploy_rollback-action_1"`),
+				Check:  testDeployments("rollback-action", "aaa:success", "bbb:success", "ccc:success", "bbb:success", "aaa:success"),
+			},
+		},
+	})
+}
+
+const testRollbackActionConfig = `
+provider "encore" {
+	auth_key = "test"
+	env = "rollback-action"
+}
+
+action "encore_rollback" "rollback" {
+	config {
+		%[2]s
+	}
+}
+
+resource "terraform_data" "trigger" {
+	input = "%[1]s"
+	lifecycle {
+		action_trigger {
+			events  = [after_create, after_update]
+			actions = [action.encore_rollback.rollback]
+		}
+	}
+}
+`
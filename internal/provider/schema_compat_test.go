@@ -0,0 +1,415 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"maps"
+	"os"
+	"path"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+var updateGolden = flag.Bool("update", false, "update the golden files in testdata")
+
+const schemaGoldenFile = "testdata/data_source_schemas.json"
+
+// schemaSnapshot maps the type name of each data source to its attributes,
+// keyed by path and described by their type and mode, e.g. "string, computed".
+// Elements of lists are named "*" in paths.
+type schemaSnapshot map[string]map[string]string
+
+// snapshotSchemas returns the snapshot of the schemas of the data sources,
+// most of which are built from the satisfier types by createSchema.
+func snapshotSchemas(ctx context.Context) schemaSnapshot {
+	snapshot := schemaSnapshot{}
+	for _, d := range (&EncoreProvider{}).DataSources(ctx) {
+		ds := d()
+		var meta datasource.MetadataResponse
+		ds.Metadata(ctx, datasource.MetadataRequest{ProviderTypeName: "encore"}, &meta)
+		var resp datasource.SchemaResponse
+		ds.Schema(ctx, datasource.SchemaRequest{}, &resp)
+		attrs := map[string]string{}
+		flattenAttributes(ctx, attrs, "", resp.Schema.Attributes)
+		snapshot[meta.TypeName] = attrs
+	}
+	return snapshot
+}
+
+func flattenAttributes(ctx context.Context, out map[string]string, prefix string, attrs map[string]schema.Attribute) {
+	for name, a := range attrs {
+		p := name
+		if prefix != "" {
+			p = prefix + "." + name
+		}
+		out[p] = terraformTypeName(a.GetType().TerraformType(ctx)) + ", " + attributeMode(a)
+		switch a := a.(type) {
+		case schema.SingleNestedAttribute:
+			flattenAttributes(ctx, out, p, a.Attributes)
+		case schema.ListNestedAttribute:
+			flattenAttributes(ctx, out, p+".*", a.NestedObject.Attributes)
+		}
+	}
+}
+
+func terraformTypeName(t tftypes.Type) string {
+	switch {
+	case t.Is(tftypes.List{}):
+		return "list(" + terraformTypeName(t.(tftypes.List).ElementType) + ")"
+	case t.Is(tftypes.Object{}):
+		return "object"
+	case t.Is(tftypes.String):
+		return "string"
+	case t.Is(tftypes.Number):
+		return "number"
+	case t.Is(tftypes.Bool):
+		return "bool"
+	}
+	return t.String()
+}
+
+func attributeMode(a schema.Attribute) string {
+	switch {
+	case a.IsRequired():
+		return "required"
+	case a.IsOptional() && a.IsComputed():
+		return "optional+computed"
+	case a.IsOptional():
+		return "optional"
+	}
+	return "computed"
+}
+
+// schemaChange is a difference between two schema snapshots.
+type schemaChange struct {
+	DataSource string
+	Attribute  string
+	Kind       string
+	Detail     string
+	Breaking   bool
+}
+
+func (c schemaChange) String() string {
+	s := c.DataSource
+	if c.Attribute != "" {
+		s += "." + c.Attribute
+	}
+	s += ": " + c.Kind
+	if c.Detail != "" {
+		s += " (" + c.Detail + ")"
+	}
+	return s
+}
+
+// compareSchemas classifies the changes from old to new. Removed,
+// renamed and retyped attributes break configurations referencing them,
+// as do new required attributes and attributes that must now be set.
+// A removed attribute is taken to be renamed if exactly one attribute of
+// the same type and mode was added next to it.
+func compareSchemas(old, new schemaSnapshot) []schemaChange {
+	var changes []schemaChange
+	for _, ds := range slices.Sorted(maps.Keys(old)) {
+		if _, ok := new[ds]; !ok {
+			changes = append(changes, schemaChange{DataSource: ds, Kind: "removed", Breaking: true})
+		}
+	}
+	for _, ds := range slices.Sorted(maps.Keys(new)) {
+		oldAttrs, ok := old[ds]
+		if !ok {
+			changes = append(changes, schemaChange{DataSource: ds, Kind: "added"})
+			continue
+		}
+		changes = append(changes, compareAttributes(ds, oldAttrs, new[ds])...)
+	}
+	return changes
+}
+
+func compareAttributes(ds string, old, new map[string]string) []schemaChange {
+	var changes, removed []schemaChange
+	added := map[string]bool{}
+	for _, p := range slices.Sorted(maps.Keys(new)) {
+		if _, ok := old[p]; !ok {
+			added[p] = true
+		}
+	}
+	// Attributes are sorted so that parents come before their children.
+	for _, p := range slices.Sorted(maps.Keys(old)) {
+		newDesc, ok := new[p]
+		switch {
+		case !ok:
+			removed = append(removed, schemaChange{DataSource: ds, Attribute: p, Kind: "removed", Breaking: true})
+		case newDesc != old[p]:
+			oldType, oldMode, _ := strings.Cut(old[p], ", ")
+			newType, newMode, _ := strings.Cut(newDesc, ", ")
+			if oldType != newType {
+				changes = append(changes, schemaChange{DataSource: ds, Attribute: p, Kind: "retyped",
+					Detail: oldType + " to " + newType, Breaking: true})
+			}
+			if oldMode != newMode {
+				changes = append(changes, schemaChange{DataSource: ds, Attribute: p, Kind: "mode changed",
+					Detail: oldMode + " to " + newMode, Breaking: modeBreaks(oldMode, newMode)})
+			}
+		}
+	}
+
+	renamed := map[string]string{}
+	for _, c := range removed {
+		if parent, ok := renamedParent(c.Attribute, renamed); ok {
+			// Children of a renamed attribute are covered by its rename,
+			// as long as they are unchanged.
+			to := renamed[parent] + strings.TrimPrefix(c.Attribute, parent)
+			if added[to] && new[to] == old[c.Attribute] {
+				delete(added, to)
+				continue
+			}
+		}
+		var candidates []string
+		for p := range added {
+			if path.Dir(slashed(p)) == path.Dir(slashed(c.Attribute)) && new[p] == old[c.Attribute] {
+				candidates = append(candidates, p)
+			}
+		}
+		if len(candidates) == 1 {
+			renamed[c.Attribute] = candidates[0]
+			delete(added, candidates[0])
+			c.Kind, c.Detail = "renamed", "to "+candidates[0]
+		}
+		changes = append(changes, c)
+	}
+
+	for _, p := range slices.Sorted(maps.Keys(added)) {
+		mode := new[p][strings.Index(new[p], ", ")+2:]
+		changes = append(changes, schemaChange{DataSource: ds, Attribute: p, Kind: "added", Breaking: mode == "required"})
+	}
+	slices.SortStableFunc(changes, func(a, b schemaChange) int { return strings.Compare(a.Attribute, b.Attribute) })
+	return changes
+}
+
+// renamedParent returns the closest ancestor of p that was renamed.
+func renamedParent(p string, renamed map[string]string) (string, bool) {
+	for i := strings.LastIndex(p, "."); i > 0; i = strings.LastIndex(p[:i], ".") {
+		if _, ok := renamed[p[:i]]; ok {
+			return p[:i], true
+		}
+	}
+	return "", false
+}
+
+// slashed returns the attribute path p with slashes as separators, for use with path.Dir.
+func slashed(p string) string {
+	return "/" + strings.ReplaceAll(p, ".", "/")
+}
+
+// modeBreaks reports whether changing the mode of an attribute breaks
+// configurations. Only relaxing a required attribute, or allowing a
+// computed one to be set, is safe.
+func modeBreaks(from, to string) bool {
+	switch {
+	case from == "required" && (to == "optional" || to == "optional+computed"):
+		return false
+	case from == "computed" && to == "optional+computed":
+		return false
+	case from == "optional" && to == "optional+computed":
+		return false
+	}
+	return true
+}
+
+func TestDataSourceSchemaCompatibility(t *testing.T) {
+	current := snapshotSchemas(context.Background())
+	if *updateGolden {
+		data, err := json.MarshalIndent(current, "", "  ")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(schemaGoldenFile, append(data, '\n'), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+
+	data, err := os.ReadFile(schemaGoldenFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var golden schemaSnapshot
+	if err := json.Unmarshal(data, &golden); err != nil {
+		t.Fatal(err)
+	}
+	// Additive changes cannot break configurations, so they only need to
+	// be recorded along with the next breaking change.
+	breaking := 0
+	for _, c := range compareSchemas(golden, current) {
+		if c.Breaking {
+			breaking++
+			t.Errorf("breaking change: %s", c)
+		} else {
+			t.Logf("additive change: %s", c)
+		}
+	}
+	if breaking > 0 {
+		t.Logf("If the changes are intended, record them with `go test ./internal/provider -run %s -update`, "+
+			"and note them in CHANGELOG.md", t.Name())
+	}
+}
+
+func TestCompareSchemas(t *testing.T) {
+	old := schemaSnapshot{
+		"encore_pubsub_topic": {
+			"name":             "string, required",
+			"env":              "string, optional",
+			"aws_sns":          "object, computed",
+			"aws_sns.arn":      "string, computed",
+			"gcp_pubsub":       "object, computed",
+			"gcp_pubsub.id":    "string, computed",
+			"subnets":          "list(object), computed",
+			"subnets.*.arn":    "string, computed",
+			"subnets.*.az":     "string, computed",
+			"subnets.*.vpc":    "object, computed",
+			"subnets.*.vpc.id": "string, computed",
+		},
+		"encore_cache": {
+			"name": "string, required",
+		},
+	}
+	tests := []struct {
+		name   string
+		modify func(s schemaSnapshot)
+		want   []string
+	}{
+		{
+			name:   "unchanged",
+			modify: func(s schemaSnapshot) {},
+		},
+		{
+			name: "added",
+			modify: func(s schemaSnapshot) {
+				s["encore_pubsub_topic"]["aws_sns.region"] = "string, computed"
+				s["encore_service"] = map[string]string{"name": "string, required"}
+			},
+			want: []string{
+				"encore_pubsub_topic.aws_sns.region: added",
+				"encore_service: added",
+			},
+		},
+		{
+			name: "added required",
+			modify: func(s schemaSnapshot) {
+				s["encore_pubsub_topic"]["region"] = "string, required"
+			},
+			want: []string{"encore_pubsub_topic.region: added (breaking)"},
+		},
+		{
+			name: "removed",
+			modify: func(s schemaSnapshot) {
+				delete(s["encore_pubsub_topic"], "gcp_pubsub")
+				delete(s["encore_pubsub_topic"], "gcp_pubsub.id")
+				delete(s, "encore_cache")
+			},
+			want: []string{
+				"encore_cache: removed (breaking)",
+				"encore_pubsub_topic.gcp_pubsub: removed (breaking)",
+				"encore_pubsub_topic.gcp_pubsub.id: removed (breaking)",
+			},
+		},
+		{
+			name: "renamed",
+			modify: func(s schemaSnapshot) {
+				delete(s["encore_pubsub_topic"], "subnets.*.az")
+				s["encore_pubsub_topic"]["subnets.*.zone"] = "string, computed"
+			},
+			want: []string{"encore_pubsub_topic.subnets.*.az: renamed (to subnets.*.zone) (breaking)"},
+		},
+		{
+			name: "renamed parent",
+			modify: func(s schemaSnapshot) {
+				attrs := s["encore_pubsub_topic"]
+				for _, p := range []string{"subnets.*.vpc", "subnets.*.vpc.id"} {
+					attrs[strings.Replace(p, "vpc", "network", 1)] = attrs[p]
+					delete(attrs, p)
+				}
+			},
+			want: []string{"encore_pubsub_topic.subnets.*.vpc: renamed (to subnets.*.network) (breaking)"},
+		},
+		{
+			name: "retyped",
+			modify: func(s schemaSnapshot) {
+				s["encore_pubsub_topic"]["subnets"] = "list(string), computed"
+				delete(s["encore_pubsub_topic"], "subnets.*.arn")
+				delete(s["encore_pubsub_topic"], "subnets.*.az")
+				delete(s["encore_pubsub_topic"], "subnets.*.vpc")
+				delete(s["encore_pubsub_topic"], "subnets.*.vpc.id")
+			},
+			want: []string{
+				"encore_pubsub_topic.subnets: retyped (list(object) to list(string)) (breaking)",
+				"encore_pubsub_topic.subnets.*.arn: removed (breaking)",
+				"encore_pubsub_topic.subnets.*.az: removed (breaking)",
+				"encore_pubsub_topic.subnets.*.vpc: removed (breaking)",
+				"encore_pubsub_topic.subnets.*.vpc.id: removed (breaking)",
+			},
+		},
+		{
+			name: "mode changed",
+			modify: func(s schemaSnapshot) {
+				s["encore_pubsub_topic"]["name"] = "string, optional"
+				s["encore_pubsub_topic"]["env"] = "string, required"
+			},
+			want: []string{
+				"encore_pubsub_topic.env: mode changed (optional to required) (breaking)",
+				"encore_pubsub_topic.name: mode changed (required to optional)",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var modified schemaSnapshot
+			data, _ := json.Marshal(old)
+			if err := json.Unmarshal(data, &modified); err != nil {
+				t.Fatal(err)
+			}
+			tt.modify(modified)
+			var got []string
+			for _, c := range compareSchemas(old, modified) {
+				s := c.String()
+				if c.Breaking {
+					s += " (breaking)"
+				}
+				got = append(got, s)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("compareSchemas() =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(tt.want, "\n"))
+			}
+		})
+	}
+}
+
+func TestSnapshotSchemas(t *testing.T) {
+	snapshot := snapshotSchemas(context.Background())
+	for ds, want := range map[string]map[string]string{
+		"encore_pubsub_topic": {
+			"name":        "string, required",
+			"app":         "string, optional",
+			"aws_sns":     "object, computed",
+			"aws_sns.arn": "string, computed",
+		},
+		"encore_gateway": {
+			"aws_alb.listeners":        "list(object), computed",
+			"aws_alb.listeners.*.port": "number, computed",
+			"aws_alb.listeners.*.arn":  "string, computed",
+			"k8s_deployment.name":      "string, computed",
+			"gcp_cloud_run.subnet.id":  "string, computed",
+		},
+	} {
+		for p, desc := range want {
+			if got := snapshot[ds][p]; got != desc {
+				t.Errorf("%s.%s = %q, want %q", ds, p, got, desc)
+			}
+		}
+	}
+}
@@ -41,7 +41,8 @@ func (a *ComputeInstance) GetDocs() map[string]string {
 }
 
 type GCPCloudRun struct {
-	SelfLink               string                    `tf:"id"`
+	SelfLink               string `tf:"id"`
+	Url                    string
 	ServerlessVpcConnector GCPServerlessVpcConnector `graphql:"serverlessVPCConnector"`
 	ServiceAccount         GCPServiceAccount
 	Subnet                 GCPSubnet
@@ -50,6 +51,7 @@ type GCPCloudRun struct {
 func (a *GCPCloudRun) GetDocs() map[string]string {
 	return map[string]string{
 		"id":                       "The [id](https://cloud.google.com/apis/design/resource_names#relative_resource_name) of the Cloud Run service in the form of `projects/{project}/locations/{location}/services/{service}`",
+		"url":                      "The URL of the Cloud Run service, in the form of `https://{service}-{hash}.{region}.run.app`",
 		"serverless_vpc_connector": "The serverless VPC connector. Set if the service is a Google Cloud Run service with a serverless VPC connector",
 		"subnet":                   "The subnet the Cloud Run service is associated with. Set if the service is a Google Cloud Run service with Direct VPC Access",
 		"service_account":          "The GCP service account of the Cloud Run service",
@@ -50,6 +50,7 @@ func testGCPCloudRunService(res, svcName string) resource.TestCheckFunc {
 func testGCPCloudRun(res, svcName string) resource.TestCheckFunc {
 	return resource.ComposeAggregateTestCheckFunc(
 		resource.TestCheckResourceAttr(res, "gcp_cloud_run.id", "projects/app-env/locations/northamerica-northeast1/services/"+svcName),
+		resource.TestCheckResourceAttr(res, "gcp_cloud_run.url", "https://"+svcName+"-x4t2abc3qa-nn.a.run.app"),
 		resource.TestCheckResourceAttr(res, "gcp_cloud_run.service_account.id", "projects/app-env/serviceAccounts/"+svcName+"@app-env.iam.gserviceaccount.com"),
 	)
 }
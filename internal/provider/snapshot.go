@@ -0,0 +1,123 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	"github.com/hasura/go-graphql-client"
+	"golang.org/x/oauth2"
+)
+
+// errSnapshotMode is returned for platform calls that a snapshot cannot serve.
+var errSnapshotMode = errors.New("the Encore Platform API is not available when snapshot_file is set")
+
+// snapshotClient is a PlatformClient serving the needs of environments from
+// snapshots of the GraphQL responses of the platform, without network access.
+//
+// The snapshot path is either a single file holding the snapshot of the
+// provider environment, or a directory of snapshots named <env>.json,
+// optionally in a subdirectory per app.
+type snapshotClient struct {
+	path       string
+	appSlug    string
+	defaultEnv string
+	gql        *graphql.Client
+}
+
+func newSnapshotClient(path, appSlug, defaultEnv string) PlatformClient {
+	c := &snapshotClient{path: path, appSlug: appSlug, defaultEnv: defaultEnv}
+	c.gql = graphql.NewClient("snapshot:///graphql", c)
+	return c
+}
+
+func (c *snapshotClient) Auth(ctx context.Context, authKey string) error {
+	return errSnapshotMode
+}
+
+func (c *snapshotClient) AuthOIDC(ctx context.Context, idToken, audience string) error {
+	return errSnapshotMode
+}
+
+func (c *snapshotClient) AuthToken(ctx context.Context, data *OAuthData) error {
+	return errSnapshotMode
+}
+
+func (c *snapshotClient) Call(ctx context.Context, method, path string, reqParams, respParams interface{}) error {
+	return errSnapshotMode
+}
+
+func (c *snapshotClient) Token(ctx context.Context) (*oauth2.Token, error) {
+	return nil, errSnapshotMode
+}
+
+func (c *snapshotClient) GQL() *graphql.Client {
+	return c.gql
+}
+
+func (c *snapshotClient) AppSlug() string {
+	return c.appSlug
+}
+
+// Do serves a GraphQL request for needs from the snapshot of the
+// requested app and environment.
+func (c *snapshotClient) Do(req *http.Request) (*http.Response, error) {
+	var reqBody struct {
+		Variables struct {
+			AppSlug string `json:"appSlug"`
+			EnvName string `json:"envName"`
+		} `json:"variables"`
+	}
+	if err := json.NewDecoder(req.Body).Decode(&reqBody); err != nil {
+		return nil, fmt.Errorf("decode request: %v", err)
+	}
+	fn, err := c.snapshotFile(reqBody.Variables.AppSlug, reqBody.Variables.EnvName)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(fn)
+	if err != nil {
+		return nil, fmt.Errorf("read snapshot: %v", err)
+	}
+	return &http.Response{
+		Status:     "200 OK",
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(bytes.NewReader(data)),
+		Request:    req,
+	}, nil
+}
+
+// snapshotFile returns the path of the snapshot of the given app and environment.
+func (c *snapshotClient) snapshotFile(appSlug, envName string) (string, error) {
+	fi, err := os.Stat(c.path)
+	if err != nil {
+		return "", fmt.Errorf("read snapshot: %v", err)
+	}
+	if !fi.IsDir() {
+		if envName != c.defaultEnv {
+			return "", fmt.Errorf("snapshot %s only holds environment %s, not %s", c.path, c.defaultEnv, envName)
+		}
+		return c.path, nil
+	}
+	var candidates []string
+	if appSlug != "" {
+		candidates = append(candidates, filepath.Join(c.path, appSlug, envName+".json"))
+	}
+	candidates = append(candidates, filepath.Join(c.path, envName+".json"))
+	for _, fn := range candidates {
+		if _, err := os.Stat(fn); err == nil {
+			return fn, nil
+		} else if !errors.Is(err, fs.ErrNotExist) {
+			return "", fmt.Errorf("read snapshot: %v", err)
+		}
+	}
+	return "", fmt.Errorf("no snapshot of environment %s in %s", envName, c.path)
+}
@@ -0,0 +1,111 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/hashicorp/terraform-plugin-framework/action"
+	"github.com/hashicorp/terraform-plugin-framework/action/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var _ action.ActionWithConfigure = &SnapshotAction{}
+
+func NewSnapshotAction() action.Action {
+	return &SnapshotAction{}
+}
+
+type SnapshotAction struct {
+	platformAction
+	needs *NeedsData
+}
+
+// SnapshotActionModel describes the action data model.
+type SnapshotActionModel struct {
+	App  types.String `tfsdk:"app"`
+	Env  types.String `tfsdk:"env"`
+	Path types.String `tfsdk:"path"`
+}
+
+func (a *SnapshotAction) Metadata(ctx context.Context, req action.MetadataRequest, resp *action.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_snapshot"
+}
+
+func (a *SnapshotAction) Schema(ctx context.Context, req action.SchemaRequest, resp *action.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Writes a snapshot of the Encore resources of an environment to a file, " +
+			"for use as the provider `snapshot_file` to plan without access to the Encore Platform.",
+		Attributes: map[string]schema.Attribute{
+			"app": schema.StringAttribute{
+				MarkdownDescription: "The slug of the app to snapshot. Defaults to the provider app",
+				Optional:            true,
+			},
+			"env": schema.StringAttribute{
+				MarkdownDescription: "The environment to snapshot. Defaults to the provider environment",
+				Optional:            true,
+			},
+			"path": schema.StringAttribute{
+				MarkdownDescription: "The file to write the snapshot to, e.g. `snapshots/<env>.json`. Parent directories are created as needed",
+				Required:            true,
+			},
+		},
+	}
+}
+
+func (a *SnapshotAction) Configure(ctx context.Context, req action.ConfigureRequest, resp *action.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	needs, ok := req.ProviderData.(*NeedsData)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Action Configure Type",
+			fmt.Sprintf("Expected *NeedsData, received %T", req.ProviderData),
+		)
+		return
+	}
+
+	a.client = needs.client
+	a.defaultEnv = needs.defaultEnv
+	a.needs = needs
+}
+
+func (a *SnapshotAction) Invoke(ctx context.Context, req action.InvokeRequest, resp *action.InvokeResponse) {
+	var data SnapshotActionModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	} else if a.needs == nil {
+		resp.Diagnostics.AddError("Unconfigured Action", "The provider has not been configured, so the Encore resources cannot be fetched.")
+		return
+	}
+	appSlug, envName, fn := data.App.ValueString(), a.env(data.Env), data.Path.ValueString()
+	if appSlug == "" {
+		appSlug = a.client.AppSlug()
+	}
+	if appSlug == "" {
+		resp.Diagnostics.AddAttributeError(path.Root("app"), "Missing App",
+			"The provider credentials are not scoped to an app. Set `app` on the provider or action.")
+		return
+	}
+
+	snapshot, err := a.needs.Snapshot(ctx, appSlug, envName)
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to fetch Encore resources of %s, got error: %s", envName, err))
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(fn), 0o755); err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("path"), "Snapshot Error", fmt.Sprintf("Unable to create snapshot directory, got error: %s", err))
+		return
+	}
+	if err := os.WriteFile(fn, append(snapshot, '\n'), 0o644); err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("path"), "Snapshot Error", fmt.Sprintf("Unable to write snapshot, got error: %s", err))
+		return
+	}
+	a.progress(ctx, resp, fmt.Sprintf("Wrote snapshot of %s to %s", envName, fn))
+}
@@ -0,0 +1,172 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/action"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"github.com/hashicorp/terraform-plugin-testing/tfversion"
+	"github.com/hasura/go-graphql-client"
+)
+
+// testFakeSnapshot returns the snapshot of env that the encore_snapshot
+// action writes: the response of the fake platform to the needs query.
+func testFakeSnapshot(env string) ([]byte, error) {
+	n := NewNeedsData(nil, env, (&EncoreProvider{}).DataSources(context.Background()))
+	vars := n.queryVars(needsKey{app: "test", env: env})
+	query, err := graphql.ConstructQuery(&needsQuery{}, vars, graphql.OperationName(needsOperation))
+	if err != nil {
+		return nil, err
+	}
+	sels, err := parseFakeQuery(query)
+	if err != nil {
+		return nil, err
+	}
+	// The fake platform gets the variables as decoded from the request.
+	var jsonVars map[string]interface{}
+	if b, err := json.Marshal(vars); err != nil {
+		return nil, err
+	} else if err := json.Unmarshal(b, &jsonVars); err != nil {
+		return nil, err
+	}
+	platform := newFakePlatform(newTestPlatformState())
+	defer platform.Close()
+	data, err := evalFakeSelection(platform.root(), sels, jsonVars)
+	if err != nil {
+		return nil, err
+	}
+	return json.MarshalIndent(map[string]interface{}{"data": data}, "", "  ")
+}
+
+// writeFakeSnapshots writes the snapshots of envs to dir, named <env>.json.
+func writeFakeSnapshots(t *testing.T, dir string, envs ...string) {
+	t.Helper()
+	for _, env := range envs {
+		snapshot, err := testFakeSnapshot(env)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, env+".json"), append(snapshot, '\n'), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// testSnapshotEquals checks that the snapshot fn holds the response of the
+// fake platform to the needs query of env.
+func testSnapshotEquals(fn, env string) resource.TestCheckFunc {
+	return func(*terraform.State) error {
+		want, err := testFakeSnapshot(env)
+		if err != nil {
+			return err
+		}
+		data, err := os.ReadFile(fn)
+		if err != nil {
+			return err
+		}
+		// Compare the indented JSON of both, to point at the first difference.
+		var got bytes.Buffer
+		if err := json.Indent(&got, bytes.TrimSpace(data), "", "  "); err != nil {
+			return fmt.Errorf("decode %s: %v", fn, err)
+		}
+		gotLines, wantLines := strings.Split(got.String(), "\n"), strings.Split(string(want), "\n")
+		for i := range max(len(gotLines), len(wantLines)) {
+			if i >= len(gotLines) || i >= len(wantLines) || gotLines[i] != wantLines[i] {
+				return fmt.Errorf("snapshot %s differs from the needs of %s at line %d:\n%s",
+					fn, env, i+1, strings.Join(gotLines[max(0, i-3):min(len(gotLines), i+3)], "\n"))
+			}
+		}
+		return nil
+	}
+}
+
+func TestSnapshotAction(t *testing.T) {
+	dir := t.TempDir()
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testV6ProviderFactories,
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_14_0),
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testSnapshotActionConfig, filepath.Join(dir, "test", "eks.json")),
+				Check:  testSnapshotEquals(filepath.Join(dir, "test", "eks.json"), "eks"),
+			},
+			{
+				Config: fmt.Sprintf(`
+provider "encore" {
+	snapshot_file = %q
+	app           = "test"
+	env           = "eks"
+}
+
+data "encore_service" "service" {
+	name = "cache"
+}
+`, dir),
+				Check: testEKSService("data.encore_service.service", "cache"),
+			},
+		},
+	})
+}
+
+const testSnapshotActionConfig = `
+provider "encore" {
+	auth_key = "test"
+}
+
+action "encore_snapshot" "eks" {
+	config {
+		env  = "eks"
+		path = %q
+	}
+}
+
+resource "terraform_data" "trigger" {
+	lifecycle {
+		action_trigger {
+			events  = [after_create]
+			actions = [action.encore_snapshot.eks]
+		}
+	}
+}
+`
+
+func TestSnapshotActionUnconfigured(t *testing.T) {
+	ctx := context.Background()
+	a := NewSnapshotAction().(*SnapshotAction)
+	var configureResp action.ConfigureResponse
+	a.Configure(ctx, action.ConfigureRequest{ProviderData: "not needs"}, &configureResp)
+	if !configureResp.Diagnostics.HasError() {
+		t.Errorf("got no diagnostics for provider data of the wrong type")
+	}
+
+	var schemaResp action.SchemaResponse
+	a.Schema(ctx, action.SchemaRequest{}, &schemaResp)
+	objType := schemaResp.Schema.Type().TerraformType(ctx).(tftypes.Object)
+	req := action.InvokeRequest{
+		Config: tfsdk.Config{
+			Raw: tftypes.NewValue(objType, map[string]tftypes.Value{
+				"app":  tftypes.NewValue(tftypes.String, nil),
+				"env":  tftypes.NewValue(tftypes.String, nil),
+				"path": tftypes.NewValue(tftypes.String, filepath.Join(t.TempDir(), "eks.json")),
+			}),
+			Schema: schemaResp.Schema,
+		},
+	}
+	var resp action.InvokeResponse
+	a.Invoke(ctx, req, &resp)
+	if !resp.Diagnostics.HasError() {
+		t.Errorf("got no diagnostics invoking an unconfigured action")
+	}
+}
@@ -0,0 +1,58 @@
+package provider
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestSnapshotFile(t *testing.T) {
+	dir := t.TempDir()
+	writeFakeSnapshots(t, dir, "eks", "gke")
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testSnapshotFileConfig, dir, "gke"),
+				Check:  testGKEService("data.encore_service.service", "cache"),
+			},
+			{
+				Config: fmt.Sprintf(testSnapshotFileConfig, filepath.Join(dir, "eks.json"), "eks"),
+				Check:  testEKSService("data.encore_service.service", "cache"),
+			},
+			{
+				Config: fmt.Sprintf(testSnapshotFileConfig, filepath.Join(dir, "eks.json"), "eks") + `
+data "encore_service" "gke" {
+	name = "cache"
+	env  = "gke"
+}
+`,
+				ExpectError: regexp.MustCompile(`only\s+holds\s+environment\s+eks,\s+not\s+gke`),
+			},
+			{
+				Config:      fmt.Sprintf(testSnapshotFileConfig, dir, "staging"),
+				ExpectError: regexp.MustCompile(`no snapshot of\s+environment\s+staging`),
+			},
+			{
+				Config: fmt.Sprintf(testSnapshotFileConfig, dir, "gke") + `
+data "encore_deployment" "latest" {}
+`,
+				ExpectError: regexp.MustCompile(`not\s+available\s+when\s+snapshot_file\s+is\s+set`),
+			},
+		},
+	})
+}
+
+const testSnapshotFileConfig = `
+provider "encore" {
+	snapshot_file = %q
+	env           = %q
+}
+
+data "encore_service" "service" {
+	name = "cache"
+}
+`
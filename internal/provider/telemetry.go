@@ -0,0 +1,56 @@
+package provider
+
+import (
+	"context"
+	"os"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// logSubsystem is the tflog subsystem of the Encore Platform client,
+// whose level can be set separately with TF_LOG_PROVIDER_ENCORE_PLATFORM.
+const logSubsystem = "platform"
+
+// platformLogContext returns ctx with the platform logging subsystem,
+// masking credentials in log fields as well as the given secrets anywhere
+// in log messages and fields.
+func platformLogContext(ctx context.Context, secrets ...string) context.Context {
+	ctx = tflog.NewSubsystem(ctx, logSubsystem)
+	ctx = tflog.SubsystemMaskFieldValuesWithFieldKeys(ctx, logSubsystem,
+		"auth_key", "access_token", "refresh_token", "id_token", "authorization")
+	if len(secrets) > 0 {
+		ctx = tflog.SubsystemMaskLogStrings(ctx, logSubsystem, secrets...)
+	}
+	return ctx
+}
+
+// tracer creates the OpenTelemetry spans of the provider. It does nothing
+// unless tracing is enabled with SetupTracing.
+var tracer = otel.Tracer("github.com/encoredev/terraform-provider-encore")
+
+// SetupTracing exports OpenTelemetry spans over OTLP/HTTP if an endpoint is
+// configured with the standard OTEL_EXPORTER_OTLP_ENDPOINT or
+// OTEL_EXPORTER_OTLP_TRACES_ENDPOINT env vars. The returned function
+// flushes pending spans and must be called before exiting.
+func SetupTracing(ctx context.Context) (shutdown func(context.Context) error, err error) {
+	noop := func(context.Context) error { return nil }
+	if strings.EqualFold(os.Getenv("OTEL_SDK_DISABLED"), "true") || os.Getenv("OTEL_TRACES_EXPORTER") == "none" ||
+		(os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT") == "" && os.Getenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT") == "") {
+		return noop, nil
+	}
+	exporter, err := otlptracehttp.New(ctx)
+	if err != nil {
+		return noop, err
+	}
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(resource.Default()),
+	)
+	otel.SetTracerProvider(tp)
+	return tp.Shutdown, nil
+}
@@ -0,0 +1,123 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-log/tflogtest"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestPlatformClientTelemetry(t *testing.T) {
+	spans := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spans)))
+
+	var (
+		traceparent string
+		flaky       int
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/login/auth-key":
+			traceparent = r.Header.Get("Traceparent")
+			_, _ = w.Write([]byte(`{"ok":true,"data":{"token":{"access_token":"secret-access-token","token_type":"Bearer"},"app_slug":"my-app"}}`))
+		case "/apps/my-app/flaky":
+			// Fail once, then respond in chunks without a Content-Length.
+			if flaky++; flaky == 1 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				_, _ = w.Write([]byte(`{"ok":false,"error":{"code":"unavailable"}}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"ok":true,`))
+			w.(http.Flusher).Flush()
+			_, _ = w.Write([]byte(`"data":{}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"ok":false,"error":{"code":"not_found"}}`))
+		}
+	}))
+	defer srv.Close()
+
+	var output bytes.Buffer
+	ctx := tflogtest.RootLogger(context.Background(), &output)
+	t.Setenv("TF_LOG_PROVIDER_ENCORE_PLATFORM", "TRACE")
+	client := NewPlatformClient("test", ClientOptions{BaseURL: srv.URL})
+	if err := client.Auth(ctx, "secret-auth-key"); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.Call(ctx, "GET", "/apps/my-app/missing", nil, nil); !IsNotFound(err) {
+		t.Fatalf("got error %v, want not found", err)
+	}
+	if err := client.Call(ctx, "GET", "/apps/my-app/flaky", nil, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	if strings.Contains(output.String(), "secret-") {
+		t.Errorf("logs contain credentials:\n%s", output.String())
+	}
+	entries, err := tflogtest.MultilineJSONDecode(&output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var requests, failures []map[string]interface{}
+	for _, e := range entries {
+		if e["@module"] != "provider.platform" {
+			continue
+		}
+		switch e["@message"] {
+		case "platform request":
+			requests = append(requests, e)
+		case "platform call failed":
+			failures = append(failures, e)
+		}
+	}
+	if len(requests) != 3 {
+		t.Fatalf("got %d platform request logs, want 3:\n%v", len(requests), entries)
+	}
+	for i, want := range []struct {
+		path    string
+		status  float64
+		retries float64
+		size    float64
+	}{
+		{"/login/auth-key", 200, 0, 109},
+		{"/apps/my-app/missing", 404, 0, 41},
+		{"/apps/my-app/flaky", 200, 1, 21},
+	} {
+		e := requests[i]
+		if e["path"] != want.path || e["status"] != want.status || e["retries"] != want.retries || e["response_size"] != want.size || e["method"] == nil || e["duration_ms"] == nil {
+			b, _ := json.Marshal(e)
+			t.Errorf("got request log %s, want path %s, status %v, %v retries and size %v", b, want.path, want.status, want.retries, want.size)
+		}
+	}
+	if len(failures) != 1 || failures[0]["code"] != "not_found" {
+		t.Errorf("got failure logs %v, want one with code not_found", failures)
+	}
+
+	ended := spans.Ended()
+	if len(ended) != 3 || ended[0].Name() != "encore.platform POST /login/auth-key" {
+		t.Fatalf("got %d spans, want 3 platform request spans", len(ended))
+	}
+	if want := ended[0].SpanContext().TraceID().String(); !strings.Contains(traceparent, want) {
+		t.Errorf("got traceparent %q, want trace %s", traceparent, want)
+	}
+}
+
+func TestSetupTracingDisabled(t *testing.T) {
+	t.Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", "")
+	t.Setenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT", "")
+	shutdown, err := SetupTracing(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := shutdown(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+}
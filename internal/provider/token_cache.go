@@ -0,0 +1,137 @@
+package provider
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"golang.org/x/oauth2"
+)
+
+// tokenCache persists OAuth tokens on disk so that they can be reused
+// across provider invocations until they expire, instead of logging in
+// with the auth key every time.
+type tokenCache struct {
+	dir string
+}
+
+// key returns the cache key of the tokens obtained with authKey from the
+// platform at baseURL. The auth key is hashed so it is never written to disk.
+func (c *tokenCache) key(authKey, baseURL string) string {
+	sum := sha256.Sum256([]byte(authKey + "\x00" + baseURL))
+	return hex.EncodeToString(sum[:])
+}
+
+func (c *tokenCache) path(key string) string {
+	return filepath.Join(c.dir, key+".json")
+}
+
+// load returns the cached OAuth data for key, or nil if there is no usable
+// cache entry. Entries that are readable by other users are ignored.
+func (c *tokenCache) load(ctx context.Context, key string) *OAuthData {
+	fn := c.path(key)
+	data, err := c.read(fn)
+	if err != nil {
+		if !errors.Is(err, fs.ErrNotExist) {
+			tflog.SubsystemWarn(ctx, logSubsystem, "ignoring cached platform token", map[string]interface{}{"path": fn, "error": err.Error()})
+		}
+		return nil
+	}
+	return data
+}
+
+func (c *tokenCache) read(fn string) (*OAuthData, error) {
+	f, err := os.Open(fn)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+	fi, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	// Windows does not support Unix permission bits.
+	if runtime.GOOS != "windows" && fi.Mode().Perm()&0o077 != 0 {
+		return nil, fmt.Errorf("insecure file permissions %s, expected no access for group and others", fi.Mode().Perm())
+	}
+	var data OAuthData
+	if err := json.NewDecoder(f).Decode(&data); err != nil {
+		return nil, fmt.Errorf("decode: %v", err)
+	} else if data.Token == nil {
+		return nil, errors.New("missing oauth token")
+	}
+	return &data, nil
+}
+
+// store writes data to the cache under key. Failures are logged rather
+// than returned, as the cache is only an optimization.
+func (c *tokenCache) store(ctx context.Context, key string, data *OAuthData) {
+	fn := c.path(key)
+	if err := c.write(fn, data); err != nil {
+		tflog.SubsystemWarn(ctx, logSubsystem, "unable to cache platform token", map[string]interface{}{"path": fn, "error": err.Error()})
+	}
+}
+
+func (c *tokenCache) write(fn string, data *OAuthData) error {
+	b, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(c.dir, 0o700); err != nil {
+		return err
+	}
+	// Write to a temporary file and rename it into place, so concurrent
+	// provider processes never read a partially written token.
+	f, err := os.CreateTemp(c.dir, ".token-*")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = os.Remove(f.Name())
+	}()
+	if _, err := f.Write(b); err != nil {
+		_ = f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), fn)
+}
+
+// cachingTokenSource stores the tokens of src in the token cache whenever
+// they are refreshed.
+type cachingTokenSource struct {
+	ctx   context.Context
+	src   oauth2.TokenSource
+	cache *tokenCache
+	key   string
+
+	mu   sync.Mutex
+	data OAuthData
+}
+
+func (s *cachingTokenSource) Token() (*oauth2.Token, error) {
+	token, err := s.src.Token()
+	if err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.data.Token == nil || token.AccessToken != s.data.Token.AccessToken {
+		s.data.Token = token
+		s.cache.store(s.ctx, s.key, &s.data)
+	}
+	return token, nil
+}
@@ -0,0 +1,187 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+)
+
+// testTokenPlatform is a platform that issues tokens for the auth key
+// "my-key", counting logins and refreshes.
+type testTokenPlatform struct {
+	*httptest.Server
+	logins, refreshes int
+	// expiresIn is the lifetime of issued tokens.
+	expiresIn time.Duration
+	// failRefresh rejects refresh tokens if set.
+	failRefresh bool
+}
+
+func newTestTokenPlatform(t *testing.T) *testTokenPlatform {
+	p := &testTokenPlatform{expiresIn: time.Hour}
+	p.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/login/auth-key":
+			var params struct {
+				AuthKey string `json:"auth_key"`
+			}
+			if json.NewDecoder(r.Body).Decode(&params) != nil || params.AuthKey != "my-key" {
+				w.WriteHeader(http.StatusUnauthorized)
+				_, _ = w.Write([]byte(`{"ok":false,"error":{"code":"unauthorized"}}`))
+				return
+			}
+			p.logins++
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{
+				"ok": true,
+				"data": map[string]interface{}{
+					"token": map[string]interface{}{
+						"access_token":  fmt.Sprintf("login-%d", p.logins),
+						"refresh_token": "refresh",
+						"token_type":    "Bearer",
+						"expiry":        time.Now().Add(p.expiresIn),
+					},
+					"app_slug": "my-app",
+				},
+			})
+		case "/login/oauth:refresh-token":
+			if p.failRefresh || r.FormValue("refresh_token") != "refresh" {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusBadRequest)
+				_, _ = w.Write([]byte(`{"error":"invalid_grant"}`))
+				return
+			}
+			p.refreshes++
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{
+				"access_token":  fmt.Sprintf("refresh-%d", p.refreshes),
+				"refresh_token": "refresh",
+				"token_type":    "Bearer",
+				"expires_in":    int(p.expiresIn.Seconds()),
+			})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(p.Close)
+	t.Setenv("ENCORE_API_URL", p.URL)
+	return p
+}
+
+// testCachedAuth authenticates a new client using the token cache in dir,
+// returning the access token in use.
+func testCachedAuth(t *testing.T, dir, authKey string) string {
+	t.Helper()
+	ctx := context.Background()
+	client := NewPlatformClient("test", ClientOptions{TokenCacheDir: dir})
+	if err := client.Auth(ctx, authKey); err != nil {
+		t.Fatal(err)
+	}
+	token, err := client.Token(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if client.AppSlug() != "my-app" {
+		t.Errorf("got app slug %q, want %q", client.AppSlug(), "my-app")
+	}
+	return token.AccessToken
+}
+
+func TestTokenCache(t *testing.T) {
+	t.Run("reuse", func(t *testing.T) {
+		platform := newTestTokenPlatform(t)
+		dir := filepath.Join(t.TempDir(), "tokens")
+		for i := 0; i < 2; i++ {
+			if got := testCachedAuth(t, dir, "my-key"); got != "login-1" {
+				t.Errorf("auth %d: got access token %q, want %q", i, got, "login-1")
+			}
+		}
+		if platform.logins != 1 || platform.refreshes != 0 {
+			t.Errorf("got %d logins and %d refreshes, want 1 login", platform.logins, platform.refreshes)
+		}
+		if runtime.GOOS != "windows" {
+			fi, err := os.Stat(dir)
+			if err != nil {
+				t.Fatal(err)
+			} else if fi.Mode().Perm() != 0o700 {
+				t.Errorf("got cache dir permissions %s, want %s", fi.Mode().Perm(), os.FileMode(0o700))
+			}
+		}
+	})
+
+	t.Run("refresh expired", func(t *testing.T) {
+		platform := newTestTokenPlatform(t)
+		platform.expiresIn = 0
+		dir := t.TempDir()
+		testCachedAuth(t, dir, "my-key")
+		platform.expiresIn = time.Hour
+		if got := testCachedAuth(t, dir, "my-key"); got != "refresh-1" {
+			t.Errorf("got access token %q, want %q", got, "refresh-1")
+		}
+		// The refreshed token is cached in turn.
+		if got := testCachedAuth(t, dir, "my-key"); got != "refresh-1" {
+			t.Errorf("got access token %q, want %q", got, "refresh-1")
+		}
+		if platform.logins != 1 || platform.refreshes != 1 {
+			t.Errorf("got %d logins and %d refreshes, want 1 login and 1 refresh", platform.logins, platform.refreshes)
+		}
+	})
+
+	t.Run("refresh failure", func(t *testing.T) {
+		platform := newTestTokenPlatform(t)
+		platform.expiresIn = 0
+		platform.failRefresh = true
+		dir := t.TempDir()
+		client := NewPlatformClient("test", ClientOptions{TokenCacheDir: dir})
+		if err := client.Auth(context.Background(), "my-key"); err != nil {
+			t.Fatal(err)
+		}
+		platform.expiresIn = time.Hour
+		if got := testCachedAuth(t, dir, "my-key"); got != "login-2" {
+			t.Errorf("got access token %q, want %q", got, "login-2")
+		}
+		if platform.logins != 2 || platform.refreshes != 0 {
+			t.Errorf("got %d logins and %d refreshes, want 2 logins", platform.logins, platform.refreshes)
+		}
+	})
+
+	t.Run("insecure permissions", func(t *testing.T) {
+		if runtime.GOOS == "windows" {
+			t.Skip("file permissions are not checked on windows")
+		}
+		platform := newTestTokenPlatform(t)
+		dir := t.TempDir()
+		testCachedAuth(t, dir, "my-key")
+		c := tokenCache{dir: dir}
+		if err := os.Chmod(c.path(c.key("my-key", platform.URL)), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if got := testCachedAuth(t, dir, "my-key"); got != "login-2" {
+			t.Errorf("got access token %q, want %q", got, "login-2")
+		}
+	})
+
+	t.Run("keyed by auth key and api url", func(t *testing.T) {
+		platform := newTestTokenPlatform(t)
+		dir := t.TempDir()
+		testCachedAuth(t, dir, "my-key")
+		client := NewPlatformClient("test", ClientOptions{TokenCacheDir: dir})
+		if err := client.Auth(context.Background(), "other-key"); err == nil {
+			t.Error("expected error for uncached wrong auth key")
+		}
+
+		other := newTestTokenPlatform(t)
+		if got := testCachedAuth(t, dir, "my-key"); got != "login-1" {
+			t.Errorf("got access token %q, want %q", got, "login-1")
+		}
+		if platform.logins != 1 || other.logins != 1 {
+			t.Errorf("got %d and %d logins, want 1 login per platform", platform.logins, other.logins)
+		}
+	})
+}
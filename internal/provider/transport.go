@@ -0,0 +1,109 @@
+package provider
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+)
+
+// newHTTPClient returns the HTTP client to reach the platform with, using
+// the CA bundle, proxy and client certificate of the provider
+// configuration. It returns http.DefaultClient if none are configured.
+func newHTTPClient(data EncoreProviderModel) (client *http.Client, diags diag.Diagnostics) {
+	caCert, caPath := data.CACertPEM.ValueString(), path.Root("ca_cert_pem")
+	if fn := data.CACertFile.ValueString(); fn != "" {
+		if caCert != "" {
+			diags.AddAttributeError(path.Root("ca_cert_file"), "Conflicting Attributes", "Only one of `ca_cert_pem` and `ca_cert_file` may be set.")
+			return nil, diags
+		}
+		b, err := os.ReadFile(fn)
+		if err != nil {
+			diags.AddAttributeError(path.Root("ca_cert_file"), "Invalid CA Certificate", fmt.Sprintf("Unable to read CA certificate file: %s", err))
+			return nil, diags
+		}
+		caCert, caPath = string(b), path.Root("ca_cert_file")
+	}
+
+	clientCert, clientKey, certPath := data.ClientCertPEM.ValueString(), data.ClientKeyPEM.ValueString(), path.Root("client_cert_pem")
+	if data.ClientCertFile.ValueString() != "" || data.ClientKeyFile.ValueString() != "" {
+		if clientCert != "" || clientKey != "" {
+			diags.AddAttributeError(path.Root("client_cert_file"), "Conflicting Attributes",
+				"Only one of `client_cert_pem`/`client_key_pem` and `client_cert_file`/`client_key_file` may be set.")
+			return nil, diags
+		}
+		var err error
+		if clientCert, err = readOptionalFile(data.ClientCertFile.ValueString()); err != nil {
+			diags.AddAttributeError(path.Root("client_cert_file"), "Invalid Client Certificate", fmt.Sprintf("Unable to read client certificate file: %s", err))
+			return nil, diags
+		}
+		if clientKey, err = readOptionalFile(data.ClientKeyFile.ValueString()); err != nil {
+			diags.AddAttributeError(path.Root("client_key_file"), "Invalid Client Certificate", fmt.Sprintf("Unable to read client key file: %s", err))
+			return nil, diags
+		}
+		certPath = path.Root("client_cert_file")
+	}
+
+	proxyURL := data.ProxyURL.ValueString()
+	if caCert == "" && clientCert == "" && clientKey == "" && proxyURL == "" {
+		return http.DefaultClient, diags
+	}
+
+	transport, ok := http.DefaultTransport.(*http.Transport)
+	if !ok {
+		diags.AddError("Internal Error", fmt.Sprintf("Expected *http.Transport, got %T", http.DefaultTransport))
+		return nil, diags
+	}
+	transport = transport.Clone()
+	if transport.TLSClientConfig == nil {
+		transport.TLSClientConfig = &tls.Config{}
+	}
+
+	if caCert != "" {
+		// Trust the CA in addition to the system roots, so that the
+		// platform remains reachable through intercepting proxies and directly.
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM([]byte(caCert)) {
+			diags.AddAttributeError(caPath, "Invalid CA Certificate", "No PEM encoded certificates found.")
+			return nil, diags
+		}
+		transport.TLSClientConfig.RootCAs = pool
+	}
+
+	if clientCert != "" || clientKey != "" {
+		cert, err := tls.X509KeyPair([]byte(clientCert), []byte(clientKey))
+		if err != nil {
+			diags.AddAttributeError(certPath, "Invalid Client Certificate", fmt.Sprintf("Unable to load client certificate and key: %s", err))
+			return nil, diags
+		}
+		transport.TLSClientConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	if proxyURL != "" {
+		u, err := url.Parse(proxyURL)
+		if err != nil || u.Scheme == "" || u.Host == "" {
+			diags.AddAttributeError(path.Root("proxy_url"), "Invalid Proxy URL", fmt.Sprintf("Expected an absolute URL, got %q.", proxyURL))
+			return nil, diags
+		}
+		transport.Proxy = http.ProxyURL(u)
+	}
+
+	return &http.Client{Transport: transport}, diags
+}
+
+// readOptionalFile returns the contents of the file fn, or "" if fn is empty.
+func readOptionalFile(fn string) (string, error) {
+	if fn == "" {
+		return "", nil
+	}
+	b, err := os.ReadFile(fn)
+	return string(b), err
+}
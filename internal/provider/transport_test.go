@@ -0,0 +1,234 @@
+package provider
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// testCert is a certificate and private key in PEM encoding.
+type testCert struct {
+	cert     *x509.Certificate
+	key      *ecdsa.PrivateKey
+	certPEM  string
+	keyPEM   string
+	tlsCerts []tls.Certificate
+}
+
+// newTestCert issues a certificate for 127.0.0.1, signed by parent or
+// self-signed as a CA if parent is nil.
+func newTestCert(t *testing.T, parent *testCert) *testCert {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: "test"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		IPAddresses:  []net.IP{net.IPv4(127, 0, 0, 1)},
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+	}
+	signer, signerKey := tmpl, key
+	if parent == nil {
+		tmpl.IsCA, tmpl.BasicConstraintsValid = true, true
+		tmpl.KeyUsage |= x509.KeyUsageCertSign
+	} else {
+		signer, signerKey = parent.cert, parent.key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, signer, &key.PublicKey, signerKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := &testCert{
+		key:     key,
+		certPEM: string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})),
+		keyPEM:  string(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})),
+	}
+	if c.cert, err = x509.ParseCertificate(der); err != nil {
+		t.Fatal(err)
+	}
+	tlsCert, err := tls.X509KeyPair([]byte(c.certPEM), []byte(c.keyPEM))
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.tlsCerts = []tls.Certificate{tlsCert}
+	return c
+}
+
+// testLoginHandler answers auth key logins for any key.
+func testLoginHandler(w http.ResponseWriter, r *http.Request) {
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{
+		"ok": true,
+		"data": map[string]interface{}{
+			"token":    map[string]interface{}{"access_token": "access", "token_type": "Bearer"},
+			"app_slug": "my-app",
+		},
+	})
+}
+
+func TestHTTPClientTLS(t *testing.T) {
+	ca := newTestCert(t, nil)
+	serverCert, clientCert := newTestCert(t, ca), newTestCert(t, ca)
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(ca.cert)
+
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(testLoginHandler))
+	srv.TLS = &tls.Config{
+		Certificates: serverCert.tlsCerts,
+		ClientCAs:    clientCAs,
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+	}
+	srv.StartTLS()
+	defer srv.Close()
+
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		fn := filepath.Join(dir, name)
+		if err := os.WriteFile(fn, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		return fn
+	}
+	caFile, certFile, keyFile := write("ca.pem", ca.certPEM), write("cert.pem", clientCert.certPEM), write("key.pem", clientCert.keyPEM)
+
+	tests := []struct {
+		name    string
+		config  EncoreProviderModel
+		wantErr string
+	}{
+		{
+			name:    "untrusted server",
+			config:  EncoreProviderModel{ClientCertPEM: types.StringValue(clientCert.certPEM), ClientKeyPEM: types.StringValue(clientCert.keyPEM)},
+			wantErr: "certificate signed by unknown authority",
+		},
+		{
+			name:    "missing client cert",
+			config:  EncoreProviderModel{CACertPEM: types.StringValue(ca.certPEM)},
+			wantErr: "certificate required",
+		},
+		{
+			name: "pem",
+			config: EncoreProviderModel{
+				CACertPEM:     types.StringValue(ca.certPEM),
+				ClientCertPEM: types.StringValue(clientCert.certPEM),
+				ClientKeyPEM:  types.StringValue(clientCert.keyPEM),
+			},
+		},
+		{
+			name: "files",
+			config: EncoreProviderModel{
+				CACertFile:     types.StringValue(caFile),
+				ClientCertFile: types.StringValue(certFile),
+				ClientKeyFile:  types.StringValue(keyFile),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			httpClient, diags := newHTTPClient(tt.config)
+			if diags.HasError() {
+				t.Fatalf("got diagnostics %v", diags)
+			}
+			client := NewPlatformClient("test", ClientOptions{BaseURL: srv.URL, HTTPClient: httpClient})
+			err := client.Auth(context.Background(), "my-key")
+			if tt.wantErr == "" && err != nil {
+				t.Fatal(err)
+			} else if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Fatalf("got error %v, want error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestHTTPClientProxy(t *testing.T) {
+	var proxied []string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxied = append(proxied, r.URL.String())
+		testLoginHandler(w, r)
+	}))
+	defer proxy.Close()
+
+	httpClient, diags := newHTTPClient(EncoreProviderModel{ProxyURL: types.StringValue(proxy.URL)})
+	if diags.HasError() {
+		t.Fatalf("got diagnostics %v", diags)
+	}
+	client := NewPlatformClient("test", ClientOptions{BaseURL: "http://platform.example", HTTPClient: httpClient})
+	if err := client.Auth(context.Background(), "my-key"); err != nil {
+		t.Fatal(err)
+	}
+	if len(proxied) != 1 || proxied[0] != "http://platform.example/login/auth-key" {
+		t.Errorf("got proxied requests %v", proxied)
+	}
+}
+
+func TestHTTPClientErrors(t *testing.T) {
+	cert := newTestCert(t, nil)
+	tests := []struct {
+		name    string
+		config  EncoreProviderModel
+		wantErr string
+	}{
+		{
+			name:    "conflicting ca",
+			config:  EncoreProviderModel{CACertPEM: types.StringValue(cert.certPEM), CACertFile: types.StringValue("ca.pem")},
+			wantErr: "Conflicting Attributes",
+		},
+		{
+			name:    "invalid ca",
+			config:  EncoreProviderModel{CACertPEM: types.StringValue("not a certificate")},
+			wantErr: "Invalid CA Certificate",
+		},
+		{
+			name:    "missing ca file",
+			config:  EncoreProviderModel{CACertFile: types.StringValue(filepath.Join(t.TempDir(), "missing.pem"))},
+			wantErr: "Invalid CA Certificate",
+		},
+		{
+			name:    "missing client key",
+			config:  EncoreProviderModel{ClientCertPEM: types.StringValue(cert.certPEM)},
+			wantErr: "Invalid Client Certificate",
+		},
+		{
+			name:    "invalid proxy",
+			config:  EncoreProviderModel{ProxyURL: types.StringValue("proxy:3128")},
+			wantErr: "Invalid Proxy URL",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, diags := newHTTPClient(tt.config)
+			if !diags.HasError() || diags[0].Summary() != tt.wantErr {
+				t.Errorf("got diagnostics %v, want %q", diags, tt.wantErr)
+			}
+		})
+	}
+
+	if client, diags := newHTTPClient(EncoreProviderModel{}); diags.HasError() || client != http.DefaultClient {
+		t.Errorf("got client %v and diagnostics %v, want http.DefaultClient", client, diags)
+	}
+}
@@ -0,0 +1,29 @@
+package provider
+
+import (
+	"context"
+	"time"
+)
+
+// pollInterval is how often long-running platform operations are polled.
+// It is overridden in tests.
+var pollInterval = 10 * time.Second
+
+// waitFor calls check until it reports done, returns an error or ctx is done.
+func waitFor(ctx context.Context, check func(ctx context.Context) (done bool, err error)) error {
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+	for {
+		done, err := check(ctx)
+		if err != nil {
+			return err
+		} else if done {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
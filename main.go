@@ -39,7 +39,16 @@ func main() {
 		Debug:   debug,
 	}
 
-	err := providerserver.Serve(context.Background(), provider.New(version), opts)
+	ctx := context.Background()
+	shutdownTracing, err := provider.SetupTracing(ctx)
+	if err != nil {
+		log.Printf("unable to set up tracing: %v", err)
+	}
+
+	err = providerserver.Serve(ctx, provider.New(version), opts)
+	if shutdownErr := shutdownTracing(ctx); shutdownErr != nil {
+		log.Printf("unable to flush traces: %v", shutdownErr)
+	}
 
 	if err != nil {
 		log.Fatal(err.Error())